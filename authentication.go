@@ -0,0 +1,764 @@
+package steam
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Steam Guard confirmation types an auth session may ask for, see
+// EAuthSessionGuardType.
+const (
+	AuthGuardTypeUnknown = iota
+	AuthGuardTypeNone
+	AuthGuardTypeEmailCode
+	AuthGuardTypeDeviceCode
+	AuthGuardTypeDeviceConfirmation
+	AuthGuardTypeEmailConfirmation
+	AuthGuardTypeMachineToken
+)
+
+const (
+	authPlatformTypeMobileApp = 3
+	authPersistencePersistent = 1
+	authWebsiteID             = "Mobile"
+	authDeviceFriendlyName    = "Android Phone"
+	authPollTimeout           = 2 * time.Minute
+	qrPollTimeout             = 5 * time.Minute
+
+	apiGetPasswordRSAPublicKey             = "https://api.steampowered.com/IAuthenticationService/GetPasswordRSAPublicKey/v1/?"
+	apiBeginAuthSessionViaCredentials      = "https://api.steampowered.com/IAuthenticationService/BeginAuthSessionViaCredentials/v1/"
+	apiBeginAuthSessionViaQR               = "https://api.steampowered.com/IAuthenticationService/BeginAuthSessionViaQR/v1/"
+	apiUpdateAuthSessionWithSteamGuardCode = "https://api.steampowered.com/IAuthenticationService/UpdateAuthSessionWithSteamGuardCode/v1/"
+	apiPollAuthSessionStatus               = "https://api.steampowered.com/IAuthenticationService/PollAuthSessionStatus/v1/"
+	apiGenerateAccessTokenForApp           = "https://api.steampowered.com/IAuthenticationService/GenerateAccessTokenForApp/v1/"
+	apiGetAuthSessionsForAccount           = "https://api.steampowered.com/IAuthenticationService/GetAuthSessionsForAccount/v1/?"
+	apiGetAuthSessionInfo                  = "https://api.steampowered.com/IAuthenticationService/GetAuthSessionInfo/v1/"
+	apiUpdateAuthSessionWithMobileConfirm  = "https://api.steampowered.com/IAuthenticationService/UpdateAuthSessionWithMobileConfirmation/v1/"
+
+	httpFinalizeLoginURL = "https://login.steampowered.com/jwt/finalizelogin"
+
+	authRenewalTypeAllow = 1
+)
+
+// steamLoginSecure domains set by RefreshSession.
+var loginSecureDomains = []string{
+	"https://steamcommunity.com",
+	"https://store.steampowered.com",
+	"https://help.steampowered.com",
+	"https://checkout.steampowered.com",
+}
+
+var (
+	ErrAuthPollTimeout             = errors.New("timed out waiting for auth session to complete")
+	ErrUnsupportedAuthConfirmation = errors.New("unsupported steam guard confirmation type")
+	ErrCannotFinalizeLogin         = errors.New("unable to finalize login")
+	ErrCannotBeginAuthSession      = errors.New("unable to begin auth session")
+	ErrNoRefreshToken              = errors.New("session has no refresh token")
+	ErrInvalidJWT                  = errors.New("invalid JWT")
+)
+
+type AuthConfirmation struct {
+	Type              int    `json:"confirmation_type"`
+	AssociatedMessage string `json:"associated_message"`
+}
+
+// AuthSession is a pending IAuthenticationService login attempt.
+type AuthSession struct {
+	ClientID             uint64              `json:"client_id,string"`
+	RequestID            string              `json:"request_id"`
+	Interval             float64             `json:"interval"`
+	AllowedConfirmations []*AuthConfirmation `json:"allowed_confirmations"`
+	SteamID              SteamID             `json:"steamid,string"`
+	WeakToken            string              `json:"weak_token"`
+	ExtendedErrorMessage string              `json:"extended_error_message"`
+}
+
+// QRAuthSession is a pending QR login attempt, ChallengeURL is what
+// should be rendered as QR code and scanned by the Steam mobile app.
+type QRAuthSession struct {
+	AuthSession
+	ChallengeURL string `json:"challenge_url"`
+	Version      int    `json:"version"`
+}
+
+type AuthSessionStatus struct {
+	NewClientID          uint64 `json:"new_client_id,string"`
+	NewChallengeURL      string `json:"new_challenge_url"`
+	RefreshToken         string `json:"refresh_token"`
+	AccessToken          string `json:"access_token"`
+	HadRemoteInteraction bool   `json:"had_remote_interaction"`
+	AccountName          string `json:"account_name"`
+	NewGuardData         string `json:"new_guard_data"`
+}
+
+// AuthSessionInfo describes a login attempt of another device that waits
+// for approval from the mobile authenticator.
+type AuthSessionInfo struct {
+	ClientID                  uint64 `json:"-"`
+	IP                        string `json:"ip"`
+	GeoLocation               string `json:"geoloc"`
+	City                      string `json:"city"`
+	State                     string `json:"state"`
+	Country                   string `json:"country"`
+	PlatformType              int    `json:"platform_type"`
+	DeviceFriendlyName        string `json:"device_friendly_name"`
+	Version                   int    `json:"version"`
+	LoginHistory              int    `json:"login_history"`
+	RequestorLocationMismatch bool   `json:"requestor_location_mismatch"`
+	HighUsageLogin            bool   `json:"high_usage_login"`
+	RequestedPersistence      int    `json:"requested_persistence"`
+}
+
+// Allows reports whether Steam accepts the @guardType confirmation
+// for this auth session.
+func (auth *AuthSession) Allows(guardType int) bool {
+	for _, confirmation := range auth.AllowedConfirmations {
+		if confirmation.Type == guardType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (auth *AuthSession) associatedMessage(guardType int) string {
+	for _, confirmation := range auth.AllowedConfirmations {
+		if confirmation.Type == guardType {
+			return confirmation.AssociatedMessage
+		}
+	}
+
+	return ""
+}
+
+func (auth *AuthSession) pollInterval() time.Duration {
+	if auth.Interval <= 0 {
+		return 5 * time.Second
+	}
+
+	return time.Duration(auth.Interval * float64(time.Second))
+}
+
+// EResultError is a failed WebAPI call, Result is the x-eresult header.
+type EResultError struct {
+	Result int
+}
+
+func (err *EResultError) Error() string {
+	return fmt.Sprintf("steam error: eresult %d", err.Result)
+}
+
+// checkEResult converts the x-eresult header of a WebAPI response into an error.
+func checkEResult(resp *http.Response) error {
+	result := resp.Header.Get("x-eresult")
+	if result == "" || result == "1" {
+		return nil
+	}
+
+	code, err := strconv.Atoi(result)
+	if err != nil {
+		return fmt.Errorf("steam error: eresult %s", result)
+	}
+
+	return &EResultError{Result: code}
+}
+
+func encryptPassword(publicKeyMod, publicKeyExp, password string) (string, error) {
+	var n big.Int
+	n.SetString(publicKeyMod, 16)
+
+	exp, err := strconv.ParseInt(publicKeyExp, 16, 32)
+	if err != nil {
+		return "", err
+	}
+
+	pub := rsa.PublicKey{N: &n, E: int(exp)}
+	rsaOut, err := rsa.EncryptPKCS1v15(rand.Reader, &pub, []byte(password))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(rsaOut), nil
+}
+
+func (session *Session) getPasswordRSAPublicKey(ctx context.Context, accountName string) (*LoginResponse, error) {
+	resp, err := session.get(ctx, apiGetPasswordRSAPublicKey+url.Values{
+		"account_name": {accountName},
+	}.Encode())
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkEResult(resp); err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Inner *LoginResponse `json:"response"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil || response.Inner.PublicKeyMod == "" {
+		return nil, ErrInvalidUsername
+	}
+
+	return response.Inner, nil
+}
+
+func (session *Session) beginAuthSessionViaCredentials(ctx context.Context, accountName, encryptedPassword, timestamp string) (*AuthSession, error) {
+	resp, err := session.postForm(ctx, apiBeginAuthSessionViaCredentials, url.Values{
+		"account_name":         {accountName},
+		"encrypted_password":   {encryptedPassword},
+		"encryption_timestamp": {timestamp},
+		"remember_login":       {"true"},
+		"persistence":          {strconv.Itoa(authPersistencePersistent)},
+		"website_id":           {authWebsiteID},
+		"device_friendly_name": {authDeviceFriendlyName},
+		"platform_type":        {strconv.Itoa(authPlatformTypeMobileApp)},
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkEResult(resp); err != nil {
+		return nil, loginError(err)
+	}
+
+	type Response struct {
+		Inner *AuthSession `json:"response"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrCannotBeginAuthSession
+	}
+
+	return response.Inner, nil
+}
+
+func (session *Session) beginAuthSessionViaQR(ctx context.Context) (*QRAuthSession, error) {
+	resp, err := session.postForm(ctx, apiBeginAuthSessionViaQR, url.Values{
+		"website_id":           {authWebsiteID},
+		"device_friendly_name": {authDeviceFriendlyName},
+		"platform_type":        {strconv.Itoa(authPlatformTypeMobileApp)},
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkEResult(resp); err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Inner *QRAuthSession `json:"response"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrCannotBeginAuthSession
+	}
+
+	return response.Inner, nil
+}
+
+func (session *Session) updateAuthSessionWithSteamGuardCode(ctx context.Context, auth *AuthSession, code string, codeType int) error {
+	resp, err := session.postForm(ctx, apiUpdateAuthSessionWithSteamGuardCode, url.Values{
+		"client_id": {strconv.FormatUint(auth.ClientID, 10)},
+		"steamid":   {auth.SteamID.ToString()},
+		"code":      {code},
+		"code_type": {strconv.Itoa(codeType)},
+	})
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = checkEResult(resp)
+	var eresult *EResultError
+	if errors.As(err, &eresult) && eresult.Result == eresultDuplicateRequest {
+		return nil /* code was already accepted */
+	}
+
+	return loginError(err)
+}
+
+func (session *Session) pollAuthSessionStatus(ctx context.Context, auth *AuthSession) (*AuthSessionStatus, error) {
+	resp, err := session.postForm(ctx, apiPollAuthSessionStatus, url.Values{
+		"client_id":  {strconv.FormatUint(auth.ClientID, 10)},
+		"request_id": {auth.RequestID},
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkEResult(resp); err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Inner *AuthSessionStatus `json:"response"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return &AuthSessionStatus{}, nil
+	}
+
+	return response.Inner, nil
+}
+
+// waitAuthSessionStatus polls @auth until Steam hands out tokens, @onStatus
+// (may be nil) is called with every poll result.
+func (session *Session) waitAuthSessionStatus(ctx context.Context, auth *AuthSession, timeout time.Duration, onStatus func(*AuthSessionStatus)) (*AuthSessionStatus, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := session.pollAuthSessionStatus(ctx, auth)
+		if err != nil {
+			return nil, err
+		}
+
+		if status.NewClientID != 0 {
+			auth.ClientID = status.NewClientID
+		}
+
+		if onStatus != nil {
+			onStatus(status)
+		}
+
+		if len(status.RefreshToken) != 0 {
+			return status, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, ErrAuthPollTimeout
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(auth.pollInterval()):
+		}
+	}
+}
+
+func postMultipart(ctx context.Context, client *http.Client, postURL string, values map[string]string) (*http.Response, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for k, v := range values {
+		if err := writer.WriteField(k, v); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Add("Origin", httpBaseUrl)
+	req.Header.Add("Referer", httpBaseUrl+"/")
+
+	return client.Do(req)
+}
+
+// finalizeLogin exchanges @refreshToken for web cookies, every domain
+// listed by finalizelogin gets its steamLoginSecure through settoken.
+func (session *Session) finalizeLogin(ctx context.Context, refreshToken string) (SteamID, error) {
+	resp, err := postMultipart(ctx, session.client, httpFinalizeLoginURL, map[string]string{
+		"nonce":        refreshToken,
+		"sessionid":    session.sessionID,
+		"redirect_uri": httpBaseUrl + "/login/home/?goto=",
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("http error: %d", resp.StatusCode)
+	}
+
+	type TransferInfo struct {
+		URL    string            `json:"url"`
+		Params map[string]string `json:"params"`
+	}
+
+	type Response struct {
+		SteamID      SteamID         `json:"steamID,string"`
+		Error        int             `json:"error"`
+		TransferInfo []*TransferInfo `json:"transfer_info"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return 0, err
+	}
+
+	if response.Error != 0 || len(response.TransferInfo) == 0 {
+		return 0, ErrCannotFinalizeLogin
+	}
+
+	for _, transfer := range response.TransferInfo {
+		params := map[string]string{"steamID": response.SteamID.ToString()}
+		for k, v := range transfer.Params {
+			params[k] = v
+		}
+
+		transferResp, err := postMultipart(ctx, session.client, transfer.URL, params)
+		if transferResp != nil {
+			transferResp.Body.Close()
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if transferResp.StatusCode != http.StatusOK {
+			return 0, fmt.Errorf("settoken %s: http error: %d", transfer.URL, transferResp.StatusCode)
+		}
+	}
+
+	return response.SteamID, nil
+}
+
+// GetAuthSessionsForAccount returns client IDs of login attempts which
+// wait for a mobile confirmation on this account.
+func (session *Session) GetAuthSessionsForAccount() ([]uint64, error) {
+	return session.GetAuthSessionsForAccountContext(context.Background())
+}
+
+func (session *Session) GetAuthSessionsForAccountContext(ctx context.Context) ([]uint64, error) {
+	resp, err := session.get(ctx, apiGetAuthSessionsForAccount+url.Values{
+		"access_token": {session.oauth.Token},
+	}.Encode())
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkEResult(resp); err != nil {
+		return nil, err
+	}
+
+	type ClientIDs struct {
+		ClientIDs []string `json:"client_ids"`
+	}
+
+	type Response struct {
+		Inner ClientIDs `json:"response"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	clientIDs := make([]uint64, len(response.Inner.ClientIDs))
+	for i, id := range response.Inner.ClientIDs {
+		if clientIDs[i], err = strconv.ParseUint(id, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+
+	return clientIDs, nil
+}
+
+func (session *Session) GetAuthSessionInfo(clientID uint64) (*AuthSessionInfo, error) {
+	return session.GetAuthSessionInfoContext(context.Background(), clientID)
+}
+
+func (session *Session) GetAuthSessionInfoContext(ctx context.Context, clientID uint64) (*AuthSessionInfo, error) {
+	resp, err := session.postForm(ctx, apiGetAuthSessionInfo, url.Values{
+		"access_token": {session.oauth.Token},
+		"client_id":    {strconv.FormatUint(clientID, 10)},
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkEResult(resp); err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Inner *AuthSessionInfo `json:"response"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, fmt.Errorf("no auth session info for client %d", clientID)
+	}
+
+	response.Inner.ClientID = clientID
+	return response.Inner, nil
+}
+
+// GetPendingAuthSessions is a shortcut for GetAuthSessionsForAccount
+// followed by GetAuthSessionInfo on every client ID.
+func (session *Session) GetPendingAuthSessions() ([]*AuthSessionInfo, error) {
+	return session.GetPendingAuthSessionsContext(context.Background())
+}
+
+func (session *Session) GetPendingAuthSessionsContext(ctx context.Context) ([]*AuthSessionInfo, error) {
+	clientIDs, err := session.GetAuthSessionsForAccountContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*AuthSessionInfo, 0, len(clientIDs))
+	for _, clientID := range clientIDs {
+		info, err := session.GetAuthSessionInfoContext(ctx, clientID)
+		if err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// signAuthSession produces the HMAC-SHA256 signature the mobile app sends
+// along with its answer: version (2 bytes), client id and steam id
+// (8 bytes each), all little endian, keyed with the shared secret.
+func signAuthSession(sharedSecret string, version int, clientID uint64, steamID SteamID) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(sharedSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 2+8+8)
+	binary.LittleEndian.PutUint16(data, uint16(version))
+	binary.LittleEndian.PutUint64(data[2:], clientID)
+	binary.LittleEndian.PutUint64(data[10:], uint64(steamID))
+
+	hash := hmac.New(sha256.New, key)
+	if _, err = hash.Write(data); err != nil {
+		return nil, err
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// AnswerAuthSession approves or denies the login attempt @info acting as
+// the mobile authenticator holding @sharedSecret.
+func (session *Session) AnswerAuthSession(info *AuthSessionInfo, sharedSecret string, approve bool) error {
+	return session.AnswerAuthSessionContext(context.Background(), info, sharedSecret, approve)
+}
+
+func (session *Session) AnswerAuthSessionContext(ctx context.Context, info *AuthSessionInfo, sharedSecret string, approve bool) error {
+	signature, err := signAuthSession(sharedSecret, info.Version, info.ClientID, session.oauth.SteamID)
+	if err != nil {
+		return err
+	}
+
+	resp, err := session.postForm(ctx, apiUpdateAuthSessionWithMobileConfirm, url.Values{
+		"access_token": {session.oauth.Token},
+		"version":      {strconv.Itoa(info.Version)},
+		"client_id":    {strconv.FormatUint(info.ClientID, 10)},
+		"steamid":      {session.oauth.SteamID.ToString()},
+		"signature":    {base64.StdEncoding.EncodeToString(signature)},
+		"confirm":      {strconv.FormatBool(approve)},
+		"persistence":  {strconv.Itoa(info.RequestedPersistence)},
+	})
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return checkEResult(resp)
+}
+
+func (info *AuthSessionInfo) Approve(session *Session, sharedSecret string) error {
+	return session.AnswerAuthSession(info, sharedSecret, true)
+}
+
+func (info *AuthSessionInfo) Deny(session *Session, sharedSecret string) error {
+	return session.AnswerAuthSession(info, sharedSecret, false)
+}
+
+// parseJWTExpiry returns the "exp" claim of @token without verifying it.
+func parseJWTExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, ErrInvalidJWT
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	type Claims struct {
+		Expires int64 `json:"exp"`
+	}
+
+	var claims Claims
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, err
+	}
+
+	if claims.Expires == 0 {
+		return time.Time{}, ErrInvalidJWT
+	}
+
+	return time.Unix(claims.Expires, 0), nil
+}
+
+// AccessTokenExpiry returns when the access token behind the web cookies expires.
+func (session *Session) AccessTokenExpiry() (time.Time, error) {
+	return parseJWTExpiry(session.oauth.Token)
+}
+
+// RefreshTokenExpiry returns when RefreshSession stops working and a full
+// login is required again.
+func (session *Session) RefreshTokenExpiry() (time.Time, error) {
+	if len(session.refreshToken) == 0 {
+		return time.Time{}, ErrNoRefreshToken
+	}
+
+	return parseJWTExpiry(session.refreshToken)
+}
+
+// RefreshSession mints a new access token from the refresh token and
+// re-issues steamLoginSecure cookies with it, no credentials needed.
+// Steam may rotate the refresh token as well, Dump the session afterwards
+// to keep it.
+func (session *Session) RefreshSession() error {
+	return session.RefreshSessionContext(context.Background())
+}
+
+func (session *Session) RefreshSessionContext(ctx context.Context) error {
+	if len(session.refreshToken) == 0 {
+		return ErrNoRefreshToken
+	}
+
+	resp, err := session.postForm(ctx, apiGenerateAccessTokenForApp, url.Values{
+		"refresh_token": {session.refreshToken},
+		"steamid":       {session.oauth.SteamID.ToString()},
+		"renewal_type":  {strconv.Itoa(authRenewalTypeAllow)},
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = checkEResult(resp); err != nil {
+		return err
+	}
+
+	type Tokens struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	type Response struct {
+		Inner Tokens `json:"response"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return err
+	}
+
+	if len(response.Inner.AccessToken) == 0 {
+		return ErrInvalidJWT
+	}
+
+	if len(response.Inner.RefreshToken) != 0 {
+		session.refreshToken = response.Inner.RefreshToken
+	}
+
+	loginSecure := url.QueryEscape(session.oauth.SteamID.ToString() + "||" + response.Inner.AccessToken)
+	for _, domain := range loginSecureDomains {
+		domainURL, err := url.Parse(domain)
+		if err != nil {
+			return err
+		}
+
+		session.client.Jar.SetCookies(domainURL, []*http.Cookie{
+			{Name: "steamLoginSecure", Value: loginSecure, Path: "/", Secure: true, HttpOnly: true},
+		})
+	}
+
+	session.oauth.Token = response.Inner.AccessToken
+	session.oauth.LoginSecure = loginSecure
+	return nil
+}
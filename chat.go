@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -90,7 +91,11 @@ type ChatFriendResponse struct {
 }
 
 func (session *Session) ChatLogin(uiMode string) error {
-	resp, err := session.client.PostForm(apiUserPresenceLogin, url.Values{
+	return session.ChatLoginContext(context.Background(), uiMode)
+}
+
+func (session *Session) ChatLoginContext(ctx context.Context, uiMode string) error {
+	resp, err := session.postForm(ctx, apiUserPresenceLogin, url.Values{
 		"ui_mode":      {uiMode},
 		"access_token": {session.oauth.Token},
 	})
@@ -121,7 +126,11 @@ func (session *Session) ChatLogin(uiMode string) error {
 }
 
 func (session *Session) ChatLogoff() error {
-	resp, err := session.client.PostForm(apiUserPresenceLogoff, url.Values{
+	return session.ChatLogoffContext(context.Background())
+}
+
+func (session *Session) ChatLogoffContext(ctx context.Context) error {
+	resp, err := session.postForm(ctx, apiUserPresenceLogoff, url.Values{
 		"access_token": {session.oauth.Token},
 		"umqid":        {session.umqID},
 	})
@@ -141,7 +150,11 @@ func (session *Session) ChatLogoff() error {
 }
 
 func (session *Session) ChatSendMessage(sid SteamID, message, messageType string) error {
-	resp, err := session.client.PostForm(apiUserPresenceMessage, url.Values{
+	return session.ChatSendMessageContext(context.Background(), sid, message, messageType)
+}
+
+func (session *Session) ChatSendMessageContext(ctx context.Context, sid SteamID, message, messageType string) error {
+	resp, err := session.postForm(ctx, apiUserPresenceMessage, url.Values{
 		"access_token": {session.oauth.Token},
 		"steamid_dst":  {sid.ToString()},
 		"text":         {message},
@@ -169,7 +182,11 @@ func (session *Session) ChatSendMessage(sid SteamID, message, messageType string
 }
 
 func (session *Session) ChatPoll(timeoutSeconds string) (*ChatResponse, error) {
-	resp, err := session.client.PostForm(apiUserPresencePoll, url.Values{
+	return session.ChatPollContext(context.Background(), timeoutSeconds)
+}
+
+func (session *Session) ChatPollContext(ctx context.Context, timeoutSeconds string) (*ChatResponse, error) {
+	resp, err := session.postForm(ctx, apiUserPresencePoll, url.Values{
 		"umqid":          {session.umqID},
 		"access_token":   {session.oauth.Token},
 		"message":        {strconv.FormatUint(uint64(session.chatMessage), 10)},
@@ -199,7 +216,11 @@ func (session *Session) ChatPoll(timeoutSeconds string) (*ChatResponse, error) {
 }
 
 func (session *Session) ChatFriendState(sid SteamID) (*ChatFriendResponse, error) {
-	resp, err := session.client.Get("https://steamcommunity.com/chat/friendstate/" + strconv.FormatUint(uint64(sid.GetAccountID()), 10))
+	return session.ChatFriendStateContext(context.Background(), sid)
+}
+
+func (session *Session) ChatFriendStateContext(ctx context.Context, sid SteamID) (*ChatFriendResponse, error) {
+	resp, err := session.get(ctx, "https://steamcommunity.com/chat/friendstate/"+strconv.FormatUint(uint64(sid.GetAccountID()), 10))
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -208,6 +229,10 @@ func (session *Session) ChatFriendState(sid SteamID) (*ChatFriendResponse, error
 		return nil, err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return nil, err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
 	}
@@ -221,7 +246,11 @@ func (session *Session) ChatFriendState(sid SteamID) (*ChatFriendResponse, error
 }
 
 func (session *Session) ChatLog(partner uint32) ([]*ChatLogMessage, error) {
-	resp, err := session.client.PostForm(fmt.Sprintf("https://steamcommunity.com/chat/chatlog/%d", partner), url.Values{
+	return session.ChatLogContext(context.Background(), partner)
+}
+
+func (session *Session) ChatLogContext(ctx context.Context, partner uint32) ([]*ChatLogMessage, error) {
+	resp, err := session.postForm(ctx, fmt.Sprintf("https://steamcommunity.com/chat/chatlog/%d", partner), url.Values{
 		"sessionid": {session.sessionID},
 	})
 	if resp != nil {
@@ -232,6 +261,10 @@ func (session *Session) ChatLog(partner uint32) ([]*ChatLogMessage, error) {
 		return nil, err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return nil, err
+	}
+
 	log := []*ChatLogMessage{}
 	if err = json.NewDecoder(resp.Body).Decode(&log); err != nil {
 		return nil, err
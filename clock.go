@@ -0,0 +1,173 @@
+package steam
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultProbeFrequency         = time.Hour
+	defaultAdjustedProbeFrequency = 5 * time.Minute
+	defaultSkewTolerance          = 60 * time.Second
+)
+
+// Clock tells the time codes are generated for, Session uses SystemClock
+// unless told otherwise with SetClock, tests can plug in a fake one.
+type Clock interface {
+	Now() time.Time
+}
+
+// contextClock is implemented by clocks that may need the network to
+// answer, like TimeAligner.
+type contextClock interface {
+	NowContext(ctx context.Context) (time.Time, error)
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+var SystemClock Clock = systemClock{}
+
+// TimeAligner is a Clock following Steam server time, it takes the offset
+// of every ITwoFactorService/QueryTime answer and queries again once
+// ProbeFrequencySeconds have passed, or AdjustedTimeProbeFrequencySeconds
+// if the drift exceeded SkewToleranceSeconds.
+type TimeAligner struct {
+	local Clock
+
+	mu                     sync.Mutex
+	offset                 time.Duration
+	aligned                bool
+	nextProbe              time.Time
+	probeFrequency         time.Duration
+	adjustedProbeFrequency time.Duration
+	skewTolerance          time.Duration
+}
+
+// NewTimeAligner returns an aligner on top of @local, nil means SystemClock.
+func NewTimeAligner(local Clock) *TimeAligner {
+	if local == nil {
+		local = SystemClock
+	}
+
+	return &TimeAligner{
+		local:                  local,
+		probeFrequency:         defaultProbeFrequency,
+		adjustedProbeFrequency: defaultAdjustedProbeFrequency,
+		skewTolerance:          defaultSkewTolerance,
+	}
+}
+
+// Offset returns the difference between Steam and the local clock.
+func (aligner *TimeAligner) Offset() time.Duration {
+	aligner.mu.Lock()
+	defer aligner.mu.Unlock()
+
+	return aligner.offset
+}
+
+// Now returns Steam time as of the last alignment, it never queries Steam.
+func (aligner *TimeAligner) Now() time.Time {
+	aligner.mu.Lock()
+	defer aligner.mu.Unlock()
+
+	return aligner.local.Now().Add(aligner.offset)
+}
+
+// NowContext aligns first if that was never done or the last probe is too old.
+func (aligner *TimeAligner) NowContext(ctx context.Context) (time.Time, error) {
+	if aligner.stale() {
+		if err := aligner.AlignContext(ctx); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return aligner.Now(), nil
+}
+
+func (aligner *TimeAligner) stale() bool {
+	aligner.mu.Lock()
+	defer aligner.mu.Unlock()
+
+	return !aligner.aligned || !aligner.local.Now().Before(aligner.nextProbe)
+}
+
+func (aligner *TimeAligner) Align() error {
+	return aligner.AlignContext(context.Background())
+}
+
+func (aligner *TimeAligner) AlignContext(ctx context.Context) error {
+	timeTip, err := GetTimeTipContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	aligner.Update(timeTip)
+	return nil
+}
+
+// Update applies @timeTip as if it was just received from Steam, nil is ignored.
+func (aligner *TimeAligner) Update(timeTip *ServerTimeTip) {
+	if timeTip == nil {
+		return
+	}
+
+	aligner.mu.Lock()
+	defer aligner.mu.Unlock()
+
+	if timeTip.SkewToleranceSeconds != 0 {
+		aligner.skewTolerance = time.Duration(timeTip.SkewToleranceSeconds) * time.Second
+	}
+
+	if timeTip.ProbeFrequencySeconds != 0 {
+		aligner.probeFrequency = time.Duration(timeTip.ProbeFrequencySeconds) * time.Second
+	}
+
+	if timeTip.AdjustedTimeProbeFrequencySeconds != 0 {
+		aligner.adjustedProbeFrequency = time.Duration(timeTip.AdjustedTimeProbeFrequencySeconds) * time.Second
+	}
+
+	now := aligner.local.Now()
+	offset := time.Unix(timeTip.Time, 0).Sub(now)
+
+	drift := offset - aligner.offset
+	if drift < 0 {
+		drift = -drift
+	}
+
+	// A large jump may not be over yet, check again sooner.
+	aligner.nextProbe = now.Add(aligner.probeFrequency)
+	if aligner.aligned && drift > aligner.skewTolerance {
+		aligner.nextProbe = now.Add(aligner.adjustedProbeFrequency)
+	}
+
+	aligner.offset = offset
+	aligner.aligned = true
+}
+
+// SetClock changes the clock two factor and confirmation codes are
+// generated with, pass a TimeAligner to follow Steam server time.
+func (session *Session) SetClock(clock Clock) {
+	session.clock = clock
+}
+
+func (session *Session) Clock() Clock {
+	if session.clock == nil {
+		return SystemClock
+	}
+
+	return session.clock
+}
+
+func (session *Session) now(ctx context.Context) (time.Time, error) {
+	clock := session.Clock()
+	if clock, ok := clock.(contextClock); ok {
+		return clock.NowContext(ctx)
+	}
+
+	return clock.Now(), nil
+}
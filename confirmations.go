@@ -1,42 +1,96 @@
 package steam
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
+const confirmationPollInterval = 3 * time.Second
+
+// Confirmation.Type values
+const (
+	ConfirmationTypeTest = iota + 1
+	ConfirmationTypeTrade
+	ConfirmationTypeMarketListing
+	ConfirmationTypeFeatureOptOut
+	ConfirmationTypePhoneNumberChange
+	ConfirmationTypeAccountRecovery
+	_
+	_
+	ConfirmationTypeAPIKeyRegistration
+)
+
 type Confirmation struct {
-	ID        uint64
-	Key       uint64
-	Title     string
-	Receiving string
-	Since     string
-	OfferID   uint64
+	ID       uint64 `json:"id,string"`
+	Key      uint64 `json:"nonce,string"`
+	Type     uint32 `json:"type"`
+	TypeName string `json:"type_name"`
+	// CreatorID is the trade offer ID for trades, the listing ID for market listings.
+	CreatorID uint64   `json:"creator_id,string"`
+	Icon      string   `json:"icon"`
+	Headline  string   `json:"headline"`
+	Summary   []string `json:"summary"`
+	Accept    string   `json:"accept"`
+	Cancel    string   `json:"cancel"`
+	Multi     bool     `json:"multi"`
+	// OfferID is CreatorID for trade confirmations, 0 otherwise.
+	OfferID      uint64    `json:"-"`
+	CreationTime time.Time `json:"-"`
+}
+
+// ConfirmationDetails lists what a confirmation moves, from our side.
+type ConfirmationDetails struct {
+	Give    []*InventoryItem
+	Receive []*InventoryItem
 }
 
 var (
-	//ErrConfirmationsUnknownError = errors.New("unknown error occurred finding confirmation")
-	ErrCannotFindConfirmations   = errors.New("unable to find confirmation")
-	ErrCannotFindDescriptions    = errors.New("unable to find confirmation descriptions")
-	ErrConfirmationsDescMismatch = errors.New("cannot match confirmation with their respective descriptions")
+	ErrCannotFindConfirmations = errors.New("unable to find confirmation")
+	ErrCannotFindDetails       = errors.New("unable to find confirmation details")
+	ErrConfirmationTimeout     = errors.New("confirmation did not show up in time")
+	ErrCannotTellTradeSides    = errors.New("unable to tell which side of the trade is ours")
+
+	confirmationItemExp    = regexp.MustCompile(`BuildHover\(\s*'confiteminfo',\s*({.*?}),\s*UserYou`)
+	confirmationProfileExp = regexp.MustCompile(`/profiles/(\d{17})`)
 )
 
-func (session *Session) execConfirmationRequest(request, key, tag string, current int64, values map[string]interface{}) (*http.Response, error) {
-	params := url.Values{
+// confirmationTime returns @current, or the session clock if it is zero.
+func (session *Session) confirmationTime(ctx context.Context, current int64) (int64, error) {
+	if current != 0 {
+		return current, nil
+	}
+
+	now, err := session.now(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return now.Unix(), nil
+}
+
+func (session *Session) confirmationParams(key, tag string, current int64) url.Values {
+	return url.Values{
 		"p":   {session.deviceID},
 		"a":   {session.oauth.SteamID.ToString()},
 		"k":   {key},
 		"t":   {strconv.FormatInt(current, 10)},
-		"m":   {"android"},
+		"m":   {"react"},
 		"tag": {tag},
 	}
+}
+
+func (session *Session) execConfirmationRequest(ctx context.Context, request, key, tag string, current int64, values map[string]interface{}) (*http.Response, error) {
+	params := session.confirmationParams(key, tag, current)
 
 	for k, v := range values {
 		switch v := v.(type) {
@@ -49,16 +103,27 @@ func (session *Session) execConfirmationRequest(request, key, tag string, curren
 		}
 	}
 
-	return session.client.Get("https://steamcommunity.com/mobileconf/" + request + params.Encode())
+	return session.get(ctx, "https://steamcommunity.com/mobileconf/"+request+params.Encode())
 }
 
+// GetConfirmations lists pending mobile confirmations, a zero @current
+// means the session clock is used.
 func (session *Session) GetConfirmations(identitySecret string, current int64) ([]*Confirmation, error) {
-	key, err := GenerateConfirmationCode(identitySecret, "confirmation", current)
+	return session.GetConfirmationsContext(context.Background(), identitySecret, current)
+}
+
+func (session *Session) GetConfirmationsContext(ctx context.Context, identitySecret string, current int64) ([]*Confirmation, error) {
+	current, err := session.confirmationTime(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := GenerateConfirmationCode(identitySecret, "list", current)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := session.execConfirmationRequest("confirmation?", key, "confirmation", current, nil)
+	resp, err := session.execConfirmationRequest(ctx, "getlist?", key, "list", current, nil)
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -67,62 +132,44 @@ func (session *Session) GetConfirmations(identitySecret string, current int64) (
 		return nil, err
 	}
 
-	doc, err := goquery.NewDocumentFromReader(io.Reader(resp.Body))
-	if err != nil {
+	if err = checkLoggedIn(resp); err != nil {
 		return nil, err
 	}
 
-	/*
-		if empty := doc.Find(".mobileconf_empty"); empty != nil {
-			if done := doc.Find(".mobileconf_done"); done != nil {
-				return nil, nil
-			}
-
-			return nil, ErrConfirmationsUnknownError // FIXME: broken
-		}
-	*/
-
-	entries := doc.Find(".mobileconf_list_entry")
-	if entries == nil {
-		return nil, ErrCannotFindConfirmations
+	type Entry struct {
+		*Confirmation
+		CreationTime int64 `json:"creation_time"`
+	}
+	type Response struct {
+		Success  bool     `json:"success"`
+		NeedAuth bool     `json:"needauth"`
+		Message  string   `json:"message"`
+		Entries  []*Entry `json:"conf"`
 	}
 
-	descriptions := doc.Find(".mobileconf_list_entry_description")
-	if descriptions == nil {
-		return nil, ErrCannotFindDescriptions
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
 	}
 
-	if len(entries.Nodes) != len(descriptions.Nodes) {
-		return nil, ErrConfirmationsDescMismatch
+	if response.NeedAuth {
+		return nil, ErrNotLoggedIn
 	}
 
-	confirmations := make([]*Confirmation, 0)
-	for k, sel := range entries.Nodes {
-		confirmation := &Confirmation{}
-		for _, attr := range sel.Attr {
-			if attr.Key == "data-confid" {
-				confirmation.ID, _ = strconv.ParseUint(attr.Val, 10, 64)
-			} else if attr.Key == "data-key" {
-				confirmation.Key, _ = strconv.ParseUint(attr.Val, 10, 64)
-			} else if attr.Key == "data-creator" {
-				confirmation.OfferID, _ = strconv.ParseUint(attr.Val, 10, 64)
-			}
+	if !response.Success {
+		if len(response.Message) != 0 {
+			return nil, errors.New(response.Message)
 		}
 
-		descSel := descriptions.Nodes[k]
-		depth := 0
-		for child := descSel.FirstChild; child != nil; child = child.NextSibling {
-			for n := child.FirstChild; n != nil; n = n.NextSibling {
-				switch depth {
-				case 0:
-					confirmation.Title = n.Data
-				case 1:
-					confirmation.Receiving = n.Data
-				case 2:
-					confirmation.Since = n.Data
-				}
-				depth++
-			}
+		return nil, ErrCannotFindConfirmations
+	}
+
+	confirmations := make([]*Confirmation, 0, len(response.Entries))
+	for _, entry := range response.Entries {
+		confirmation := entry.Confirmation
+		confirmation.CreationTime = time.Unix(entry.CreationTime, 0)
+		if confirmation.Type == ConfirmationTypeTrade {
+			confirmation.OfferID = confirmation.CreatorID
 		}
 
 		confirmations = append(confirmations, confirmation)
@@ -131,7 +178,18 @@ func (session *Session) GetConfirmations(identitySecret string, current int64) (
 	return confirmations, nil
 }
 
+// AnswerConfirmation allows or cancels @confirmation, a zero @current
+// means the session clock is used.
 func (session *Session) AnswerConfirmation(confirmation *Confirmation, identitySecret, answer string, current int64) error {
+	return session.AnswerConfirmationContext(context.Background(), confirmation, identitySecret, answer, current)
+}
+
+func (session *Session) AnswerConfirmationContext(ctx context.Context, confirmation *Confirmation, identitySecret, answer string, current int64) error {
+	current, err := session.confirmationTime(ctx, current)
+	if err != nil {
+		return err
+	}
+
 	key, err := GenerateConfirmationCode(identitySecret, answer, current)
 	if err != nil {
 		return err
@@ -143,7 +201,7 @@ func (session *Session) AnswerConfirmation(confirmation *Confirmation, identityS
 		"ck":  confirmation.Key,
 	}
 
-	resp, err := session.execConfirmationRequest("ajaxop?", key, answer, current, op)
+	resp, err := session.execConfirmationRequest(ctx, "ajaxop?", key, answer, current, op)
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -152,6 +210,10 @@ func (session *Session) AnswerConfirmation(confirmation *Confirmation, identityS
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	type Response struct {
 		Success bool   `json:"success"`
 		Message string `json:"message"`
@@ -169,6 +231,443 @@ func (session *Session) AnswerConfirmation(confirmation *Confirmation, identityS
 	return nil
 }
 
+// AnswerConfirmations allows or cancels all of @confirmations with a single
+// signed request. Steam only tells whether the whole batch went through,
+// if it did not, those still pending are answered on their own to learn
+// which failed: the returned slice holds the error of each confirmation,
+// nil for those answered, in the order of @confirmations.
+func (session *Session) AnswerConfirmations(confirmations []*Confirmation, identitySecret, answer string, current int64) ([]error, error) {
+	return session.AnswerConfirmationsContext(context.Background(), confirmations, identitySecret, answer, current)
+}
+
+func (session *Session) AnswerConfirmationsContext(ctx context.Context, confirmations []*Confirmation, identitySecret, answer string, current int64) ([]error, error) {
+	results := make([]error, len(confirmations))
+	if len(confirmations) == 0 {
+		return results, nil
+	}
+
+	current, err := session.confirmationTime(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := GenerateConfirmationCode(identitySecret, answer, current)
+	if err != nil {
+		return nil, err
+	}
+
+	params := session.confirmationParams(key, answer, current)
+	params.Set("op", answer)
+	for _, confirmation := range confirmations {
+		params.Add("cid[]", strconv.FormatUint(confirmation.ID, 10))
+		params.Add("ck[]", strconv.FormatUint(confirmation.Key, 10))
+	}
+
+	resp, err := session.postForm(ctx, "https://steamcommunity.com/mobileconf/multiajaxop", params)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkLoggedIn(resp); err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Success  bool `json:"success"`
+		NeedAuth bool `json:"needauth"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if response.NeedAuth {
+		return nil, ErrNotLoggedIn
+	}
+
+	if response.Success {
+		return results, nil
+	}
+
+	// Part of the batch may have gone through, those are not pending anymore.
+	pending, err := session.GetConfirmationsContext(ctx, identitySecret, current)
+	if err != nil {
+		return nil, err
+	}
+
+	stillPending := make(map[uint64]bool, len(pending))
+	for _, confirmation := range pending {
+		stillPending[confirmation.ID] = true
+	}
+
+	for k, confirmation := range confirmations {
+		if !stillPending[confirmation.ID] {
+			continue
+		}
+
+		results[k] = session.AnswerConfirmationContext(ctx, confirmation, identitySecret, answer, current)
+		if errors.Is(results[k], ErrNotLoggedIn) {
+			return nil, results[k]
+		}
+	}
+
+	return results, nil
+}
+
+// GetConfirmationDetails returns the items on both sides of @confirmation,
+// with their descriptions when Steam knows them (Desc is nil otherwise),
+// a zero @current means the session clock is used.
+func (session *Session) GetConfirmationDetails(confirmation *Confirmation, identitySecret string, current int64) (*ConfirmationDetails, error) {
+	return session.GetConfirmationDetailsContext(context.Background(), confirmation, identitySecret, current)
+}
+
+func (session *Session) GetConfirmationDetailsContext(ctx context.Context, confirmation *Confirmation, identitySecret string, current int64) (*ConfirmationDetails, error) {
+	details, err := session.getConfirmationDetails(ctx, confirmation, identitySecret, current)
+	if err != nil {
+		return nil, err
+	}
+
+	// Items are still worth returning when their descriptions cannot be had.
+	items := append(append([]*InventoryItem{}, details.Give...), details.Receive...)
+	if len(session.apiKey) != 0 {
+		session.fillItemDescriptions(ctx, items)
+	}
+
+	return details, nil
+}
+
+// getConfirmationDetails is GetConfirmationDetailsContext without descriptions.
+func (session *Session) getConfirmationDetails(ctx context.Context, confirmation *Confirmation, identitySecret string, current int64) (*ConfirmationDetails, error) {
+	current, err := session.confirmationTime(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := "details" + strconv.FormatUint(confirmation.ID, 10)
+	key, err := GenerateConfirmationCode(identitySecret, tag, current)
+	if err != nil {
+		return nil, err
+	}
+
+	request := "details/" + strconv.FormatUint(confirmation.ID, 10) + "?"
+	resp, err := session.execConfirmationRequest(ctx, request, key, tag, current, nil)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkLoggedIn(resp); err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Success  bool   `json:"success"`
+		NeedAuth bool   `json:"needauth"`
+		HTML     string `json:"html"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if response.NeedAuth {
+		return nil, ErrNotLoggedIn
+	}
+
+	if !response.Success {
+		return nil, ErrCannotFindDetails
+	}
+
+	return parseConfirmationDetails(response.HTML, session.oauth.SteamID)
+}
+
+// parseConfirmationDetails reads the details HTML, @steamID is our account.
+func parseConfirmationDetails(html string, steamID SteamID) (*ConfirmationDetails, error) {
+	details := &ConfirmationDetails{}
+
+	// Market listings embed the listed item with its description.
+	if m := confirmationItemExp.FindStringSubmatch(html); m != nil {
+		type Asset struct {
+			AppID      uint32 `json:"appid"`
+			ContextID  uint64 `json:"contextid,string"`
+			AssetID    uint64 `json:"id,string"`
+			ClassID    uint64 `json:"classid,string"`
+			InstanceID uint64 `json:"instanceid,string"`
+			Amount     uint64 `json:"amount,string"`
+		}
+
+		asset := &Asset{}
+		if err := json.Unmarshal([]byte(m[1]), asset); err != nil {
+			return nil, err
+		}
+
+		desc := &EconItemDesc{}
+		if err := json.Unmarshal([]byte(m[1]), desc); err != nil {
+			return nil, err
+		}
+
+		details.Give = append(details.Give, &InventoryItem{
+			AppID:      asset.AppID,
+			ContextID:  asset.ContextID,
+			AssetID:    asset.AssetID,
+			ClassID:    asset.ClassID,
+			InstanceID: asset.InstanceID,
+			Amount:     asset.Amount,
+			Desc:       desc,
+		})
+		return details, nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, err
+	}
+
+	// Trades: the primary side holds the items of the offer creator, which is
+	// the partner for incoming offers, so sides are told by their owner.
+	type Side struct {
+		items []*InventoryItem
+		owner SteamID
+	}
+
+	var sides []*Side
+	doc.Find(".tradeoffer_items").Each(func(_ int, sel *goquery.Selection) {
+		side := &Side{owner: tradeSideOwner(sel)}
+		sel.Find(".trade_item").Each(func(_ int, sel *goquery.Selection) {
+			if item := parseEconomyItem(sel.AttrOr("data-economy-item", "")); item != nil {
+				side.items = append(side.items, item)
+			}
+		})
+
+		sides = append(sides, side)
+	})
+
+	// A side without a recognizable owner is ours only if the other one is not.
+	ours := -1
+	for k, side := range sides {
+		if side.owner != 0 && side.owner.GetAccountID() == steamID.GetAccountID() {
+			ours = k
+		}
+	}
+
+	if ours == -1 && len(sides) == 2 {
+		switch {
+		case sides[0].owner != 0 && sides[1].owner == 0:
+			ours = 1
+		case sides[1].owner != 0 && sides[0].owner == 0:
+			ours = 0
+		}
+	}
+
+	if ours == -1 && len(sides) != 0 {
+		return nil, ErrCannotTellTradeSides
+	}
+
+	for k, side := range sides {
+		if k == ours {
+			details.Give = append(details.Give, side.items...)
+		} else {
+			details.Receive = append(details.Receive, side.items...)
+		}
+	}
+
+	return details, nil
+}
+
+// tradeSideOwner returns whose items a ".tradeoffer_items" side holds, from
+// its avatar's "data-miniprofile" account ID or "/profiles/<steamid>" link,
+// 0 if it has neither.
+func tradeSideOwner(sel *goquery.Selection) SteamID {
+	var owner SteamID
+	if miniprofile, ok := sel.Find("[data-miniprofile]").Attr("data-miniprofile"); ok {
+		if accountID, err := strconv.ParseUint(miniprofile, 10, 32); err == nil && accountID != 0 {
+			owner.ParseDefaults(uint32(accountID))
+			return owner
+		}
+	}
+
+	sel.Find("a[href]").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+		m := confirmationProfileExp.FindStringSubmatch(a.AttrOr("href", ""))
+		if m == nil {
+			return true
+		}
+
+		steamID, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return true
+		}
+
+		owner = SteamID(steamID)
+		return false
+	})
+
+	return owner
+}
+
+// parseEconomyItem parses "classinfo/<appid>/<classid>/<instanceid>" or
+// "<appid>/<contextid>/<assetid>" item references.
+func parseEconomyItem(ref string) *InventoryItem {
+	parts := strings.Split(ref, "/")
+	if len(parts) < 3 {
+		return nil
+	}
+
+	item := &InventoryItem{Amount: 1}
+	if parts[0] == "classinfo" {
+		appID, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return nil
+		}
+
+		item.AppID = uint32(appID)
+		item.ClassID, _ = strconv.ParseUint(parts[2], 10, 64)
+		if len(parts) > 3 {
+			item.InstanceID, _ = strconv.ParseUint(parts[3], 10, 64)
+		}
+
+		return item
+	}
+
+	appID, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil
+	}
+
+	item.AppID = uint32(appID)
+	item.ContextID, _ = strconv.ParseUint(parts[1], 10, 64)
+	item.AssetID, _ = strconv.ParseUint(parts[2], 10, 64)
+	return item
+}
+
+// waitConfirmation polls confirmations until one satisfies @match and
+// returns it, or ErrConfirmationTimeout once @timeout passed.
+func (session *Session) waitConfirmation(ctx context.Context, identitySecret string, timeout time.Duration, match func(context.Context, *Confirmation) (bool, error)) (*Confirmation, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		confirmations, err := session.GetConfirmationsContext(ctx, identitySecret, 0)
+		if err != nil && ctx.Err() == nil {
+			return nil, err
+		}
+
+		for _, confirmation := range confirmations {
+			ok, err := match(ctx, confirmation)
+			if err != nil && ctx.Err() == nil {
+				return nil, err
+			}
+
+			if ok {
+				return confirmation, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ErrConfirmationTimeout
+			}
+
+			return nil, ctx.Err()
+		case <-time.After(confirmationPollInterval):
+		}
+	}
+}
+
+// AnswerTradeOfferConfirmation waits up to @timeout for the confirmation of
+// the trade offer @offerID, as sent by SendTradeOffer, and answers it.
+func (session *Session) AnswerTradeOfferConfirmation(offerID uint64, identitySecret, answer string, timeout time.Duration) error {
+	return session.AnswerTradeOfferConfirmationContext(context.Background(), offerID, identitySecret, answer, timeout)
+}
+
+func (session *Session) AnswerTradeOfferConfirmationContext(ctx context.Context, offerID uint64, identitySecret, answer string, timeout time.Duration) error {
+	confirmation, err := session.waitConfirmation(ctx, identitySecret, timeout, func(_ context.Context, confirmation *Confirmation) (bool, error) {
+		return confirmation.Type == ConfirmationTypeTrade && confirmation.CreatorID == offerID, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return session.AnswerConfirmationContext(ctx, confirmation, identitySecret, answer, 0)
+}
+
+// AnswerMarketListingConfirmation waits up to @timeout for the confirmation
+// of the market listing @listingID and answers it.
+func (session *Session) AnswerMarketListingConfirmation(listingID uint64, identitySecret, answer string, timeout time.Duration) error {
+	return session.AnswerMarketListingConfirmationContext(context.Background(), listingID, identitySecret, answer, timeout)
+}
+
+func (session *Session) AnswerMarketListingConfirmationContext(ctx context.Context, listingID uint64, identitySecret, answer string, timeout time.Duration) error {
+	confirmation, err := session.waitConfirmation(ctx, identitySecret, timeout, func(_ context.Context, confirmation *Confirmation) (bool, error) {
+		return confirmation.Type == ConfirmationTypeMarketListing && confirmation.CreatorID == listingID, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return session.AnswerConfirmationContext(ctx, confirmation, identitySecret, answer, 0)
+}
+
+// AnswerSellItemConfirmation answers the confirmation of the listing created
+// by SellItem for @item, SellItem does not tell the listing ID so listings
+// are matched by asset ID through their details. If it times out after
+// details failed to load, that error is returned instead.
+func (session *Session) AnswerSellItemConfirmation(item *InventoryItem, identitySecret, answer string, timeout time.Duration) error {
+	return session.AnswerSellItemConfirmationContext(context.Background(), item, identitySecret, answer, timeout)
+}
+
+func (session *Session) AnswerSellItemConfirmationContext(ctx context.Context, item *InventoryItem, identitySecret, answer string, timeout time.Duration) error {
+	var detailsErr error
+	checked := make(map[uint64]bool)
+	confirmation, err := session.waitConfirmation(ctx, identitySecret, timeout, func(ctx context.Context, confirmation *Confirmation) (bool, error) {
+		if confirmation.Type != ConfirmationTypeMarketListing || checked[confirmation.ID] {
+			return false, nil
+		}
+
+		// Details of another listing failing to load are no reason to give up,
+		// the error is only reported if the listing never shows up.
+		details, err := session.getConfirmationDetails(ctx, confirmation, identitySecret, 0)
+		if err != nil {
+			if errors.Is(err, ErrNotLoggedIn) {
+				return false, err
+			}
+
+			if ctx.Err() == nil {
+				detailsErr = err
+			}
+
+			return false, nil
+		}
+
+		checked[confirmation.ID] = true
+		for _, listed := range details.Give {
+			if listed.AppID == item.AppID && listed.ContextID == item.ContextID && listed.AssetID == item.AssetID {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+	if errors.Is(err, ErrConfirmationTimeout) && detailsErr != nil {
+		return detailsErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return session.AnswerConfirmationContext(ctx, confirmation, identitySecret, answer, 0)
+}
+
 func (confirmation *Confirmation) Answer(session *Session, key, answer string, current int64) error {
 	return session.AnswerConfirmation(confirmation, key, answer, current)
 }
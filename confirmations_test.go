@@ -0,0 +1,69 @@
+package steam
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Markup of a trade confirmation, the primary side is the offer creator's.
+const tradeConfirmationDetails = `<div class="mobileconf_trade_area">
+<div class="tradeoffer" id="tradeofferid_4000">
+	<div class="tradeoffer_partner">
+		<div class="playerAvatar offline" data-miniprofile="%[2]d"></div>
+	</div>
+	<div class="tradeoffer_items_ctn">
+		<div class="tradeoffer_items primary">
+			<div class="tradeoffer_items_avatar_ctn">
+				<a class="tradeoffer_avatar playerAvatar tiny" href="https://steamcommunity.com/profiles/%[3]d" data-miniprofile="%[1]d"><img></a>
+			</div>
+			<div class="tradeoffer_items_header">Creator offered:</div>
+			<div class="tradeoffer_item_list">
+				<div class="trade_item" data-economy-item="classinfo/440/101/0"></div>
+			</div>
+		</div>
+		<div class="tradeoffer_items secondary">
+			<div class="tradeoffer_items_avatar_ctn">
+				<a class="tradeoffer_avatar playerAvatar tiny" href="https://steamcommunity.com/id/vanity/"><img></a>
+			</div>
+			<div class="tradeoffer_items_header">For your:</div>
+			<div class="tradeoffer_item_list">
+				<div class="trade_item" data-economy-item="classinfo/730/202/0"></div>
+			</div>
+		</div>
+	</div>
+</div>
+</div>`
+
+func TestParseConfirmationDetailsSides(t *testing.T) {
+	var us, partner SteamID
+	us.ParseDefaults(111)
+	partner.ParseDefaults(222)
+
+	tests := []struct {
+		name    string
+		creator SteamID
+		other   SteamID
+		give    uint64
+		receive uint64
+	}{
+		// Accepting an incoming offer: the partner created it.
+		{"incoming", partner, us, 202, 101},
+		{"outgoing", us, partner, 101, 202},
+	}
+
+	for _, test := range tests {
+		html := fmt.Sprintf(tradeConfirmationDetails, test.creator.GetAccountID(), test.other.GetAccountID(), uint64(test.creator))
+		details, err := parseConfirmationDetails(html, us)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if len(details.Give) != 1 || details.Give[0].ClassID != test.give {
+			t.Fatalf("%s: give %+v, want class %d", test.name, details.Give, test.give)
+		}
+
+		if len(details.Receive) != 1 || details.Receive[0].ClassID != test.receive {
+			t.Fatalf("%s: receive %+v, want class %d", test.name, details.Receive, test.receive)
+		}
+	}
+}
@@ -131,10 +131,11 @@ func main() {
 	for i := range confirmations {
 		c := confirmations[i]
 		log.Printf("Confirmation ID: %d, Key: %d\n", c.ID, c.Key)
-		log.Printf("-> Title %s\n", c.Title)
-		log.Printf("-> Receiving %s\n", c.Receiving)
-		log.Printf("-> Since %s\n", c.Since)
-		log.Printf("-> OfferID %d\n", c.OfferID)
+		log.Printf("-> Type %s\n", c.TypeName)
+		log.Printf("-> Headline %s\n", c.Headline)
+		log.Printf("-> Summary %v\n", c.Summary)
+		log.Printf("-> Created %s\n", c.CreationTime)
+		log.Printf("-> CreatorID %d\n", c.CreatorID)
 
 		err = session.AnswerConfirmation(c, key, "allow", time.Now().Add(timeDiff).Unix())
 		if err != nil {
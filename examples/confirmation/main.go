@@ -5,7 +5,6 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"time"
 
 	"github.com/LuciusMortified/steam"
 	"github.com/joho/godotenv"
@@ -37,17 +36,15 @@ func main() {
 		log.Fatal(errors.New("specify IDENTITY_SECRET env"))
 	}
 
-	timeTip, err := steam.GetTimeTip()
-	if err != nil {
+	aligner := steam.NewTimeAligner(nil)
+	if err := aligner.Align(); err != nil {
 		log.Fatal(err)
 	}
-	log.Printf("Time tip: %#v\n", timeTip)
-
-	timeDiff := time.Duration(timeTip.Time - time.Now().Unix())
-	log.Printf("Time diff: %v\n", timeDiff)
+	log.Printf("Time offset: %v\n", aligner.Offset())
 
 	session := steam.NewSession(&http.Client{}, "", true)
-	if err := session.Login(username, password, sharedSecret, timeDiff); err != nil {
+	session.SetClock(aligner)
+	if err := session.Login(username, password, sharedSecret, 0); err != nil {
 		log.Fatal(err)
 	}
 	log.Print("Login successful")
@@ -58,7 +55,7 @@ func main() {
 	}
 	log.Print("Web Api Key: ", key)
 
-	confirmations, err := session.GetConfirmations(identitySecret, time.Now().Add(timeDiff).Unix())
+	confirmations, err := session.GetConfirmations(identitySecret, 0)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -66,12 +63,13 @@ func main() {
 	for i := range confirmations {
 		c := confirmations[i]
 		log.Printf("Confirmation ID: %d, Key: %d\n", c.ID, c.Key)
-		log.Printf("-> Title %s\n", c.Title)
-		log.Printf("-> Receiving %s\n", c.Receiving)
-		log.Printf("-> Since %s\n", c.Since)
-		log.Printf("-> OfferID %d\n", c.OfferID)
+		log.Printf("-> Type %s\n", c.TypeName)
+		log.Printf("-> Headline %s\n", c.Headline)
+		log.Printf("-> Summary %v\n", c.Summary)
+		log.Printf("-> Created %s\n", c.CreationTime)
+		log.Printf("-> CreatorID %d\n", c.CreatorID)
 
-		err = session.AnswerConfirmation(c, identitySecret, "allow", time.Now().Add(timeDiff).Unix())
+		err = session.AnswerConfirmation(c, identitySecret, "allow", 0)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/LuciusMortified/steam"
+)
+
+func main() {
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	session := steam.NewSession(&http.Client{}, "", true)
+	qr, err := session.BeginQRLogin()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Scan QR code for: %s\n", qr.ChallengeURL)
+
+	updates := make(chan *steam.AuthSessionStatus)
+	go func() {
+		for status := range updates {
+			if status.NewChallengeURL != "" {
+				log.Printf("QR code changed, scan: %s\n", status.NewChallengeURL)
+			}
+
+			if status.HadRemoteInteraction {
+				log.Print("QR code scanned, waiting for approval")
+			}
+		}
+	}()
+
+	err = session.FinishQRLogin(qr, updates)
+	close(updates)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Login successful: %d\n", session.GetSteamID())
+}
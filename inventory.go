@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -53,6 +55,7 @@ type InventoryAppStats struct {
 var inventoryContextRegexp = regexp.MustCompile("var g_rgAppContextData = (.*?);")
 
 func (session *Session) fetchInventory(
+	ctx context.Context,
 	sid SteamID,
 	appID, contextID, startAssetID uint64,
 	filters []Filter,
@@ -69,7 +72,7 @@ func (session *Session) fetchInventory(
 		params.Set("count", "250")
 	}
 
-	resp, err := session.client.Get(fmt.Sprintf(InventoryEndpoint, sid, appID, contextID) + params.Encode())
+	resp, err := session.get(ctx, fmt.Sprintf(InventoryEndpoint, sid, appID, contextID)+params.Encode())
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -168,21 +171,29 @@ func (session *Session) fetchInventory(
 }
 
 func (session *Session) GetInventory(sid SteamID, appID, contextID uint64, tradableOnly bool) ([]InventoryItem, error) {
+	return session.GetInventoryContext(context.Background(), sid, appID, contextID, tradableOnly)
+}
+
+func (session *Session) GetInventoryContext(ctx context.Context, sid SteamID, appID, contextID uint64, tradableOnly bool) ([]InventoryItem, error) {
 	filters := []Filter{}
 
 	if tradableOnly {
 		filters = append(filters, IsTradable(tradableOnly))
 	}
 
-	return session.GetFilterableInventory(sid, appID, contextID, filters)
+	return session.GetFilterableInventoryContext(ctx, sid, appID, contextID, filters)
 }
 
 func (session *Session) GetFilterableInventory(sid SteamID, appID, contextID uint64, filters []Filter) ([]InventoryItem, error) {
+	return session.GetFilterableInventoryContext(context.Background(), sid, appID, contextID, filters)
+}
+
+func (session *Session) GetFilterableInventoryContext(ctx context.Context, sid SteamID, appID, contextID uint64, filters []Filter) ([]InventoryItem, error) {
 	items := []InventoryItem{}
 	startAssetID := uint64(0)
 
 	for {
-		hasMore, lastAssetID, err := session.fetchInventory(sid, appID, contextID, startAssetID, filters, &items)
+		hasMore, lastAssetID, err := session.fetchInventory(ctx, sid, appID, contextID, startAssetID, filters, &items)
 		if err != nil {
 			return nil, err
 		}
@@ -198,7 +209,11 @@ func (session *Session) GetFilterableInventory(sid SteamID, appID, contextID uin
 }
 
 func (session *Session) GetInventoryAppStats(sid SteamID) (map[string]InventoryAppStats, error) {
-	resp, err := session.client.Get("https://steamcommunity.com/profiles/" + sid.ToString() + "/inventory")
+	return session.GetInventoryAppStatsContext(context.Background(), sid)
+}
+
+func (session *Session) GetInventoryAppStatsContext(ctx context.Context, sid SteamID) (map[string]InventoryAppStats, error) {
+	resp, err := session.get(ctx, "https://steamcommunity.com/profiles/"+sid.ToString()+"/inventory")
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -207,6 +222,10 @@ func (session *Session) GetInventoryAppStats(sid SteamID) (map[string]InventoryA
 		return nil, err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return nil, err
+	}
+
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -224,3 +243,160 @@ func (session *Session) GetInventoryAppStats(sid SteamID) (map[string]InventoryA
 
 	return inven, nil
 }
+
+// fillItemDescriptions sets Desc of @items from ISteamEconomy/GetAssetClassInfo,
+// items are looked up by class and instance, per app.
+func (session *Session) fillItemDescriptions(ctx context.Context, items []*InventoryItem) error {
+	apps := make(map[uint32][]*InventoryItem)
+	for _, item := range items {
+		if item.Desc == nil && item.ClassID != 0 {
+			apps[item.AppID] = append(apps[item.AppID], item)
+		}
+	}
+
+	for appID, appItems := range apps {
+		descriptions, err := session.getAssetClassInfo(ctx, appID, appItems)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range appItems {
+			item.Desc = descriptions.find(item.AppID, item.ClassID, item.InstanceID)
+		}
+	}
+
+	return nil
+}
+
+func (session *Session) getAssetClassInfo(ctx context.Context, appID uint32, items []*InventoryItem) (econDescriptions, error) {
+	params := url.Values{
+		"key":      {session.apiKey},
+		"appid":    {strconv.FormatUint(uint64(appID), 10)},
+		"language": {session.language},
+	}
+
+	classes := make(map[string]bool)
+	for _, item := range items {
+		key := fmt.Sprintf("%d_%d", item.ClassID, item.InstanceID)
+		if classes[key] {
+			continue
+		}
+
+		n := strconv.Itoa(len(classes))
+		params.Set("classid"+n, strconv.FormatUint(item.ClassID, 10))
+		params.Set("instanceid"+n, strconv.FormatUint(item.InstanceID, 10))
+		classes[key] = true
+	}
+	params.Set("class_count", strconv.Itoa(len(classes)))
+
+	resp, err := session.get(ctx, "https://api.steampowered.com/ISteamEconomy/GetAssetClassInfo/v1/?"+params.Encode())
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Unlike inventories, numbers are strings and lists are objects keyed by index.
+	type ClassTag struct {
+		InternalName string `json:"internal_name"`
+		Name         string `json:"name"`
+		Category     string `json:"category"`
+		CategoryName string `json:"category_name"`
+	}
+	type ClassInfo struct {
+		ClassID         uint64                 `json:"classid,string"`
+		InstanceID      uint64                 `json:"instanceid,string"`
+		Tradable        int                    `json:"tradable,string"`
+		BackgroundColor string                 `json:"background_color"`
+		IconURL         string                 `json:"icon_url"`
+		IconLargeURL    string                 `json:"icon_url_large"`
+		IconDragURL     string                 `json:"icon_drag_url"`
+		Name            string                 `json:"name"`
+		NameColor       string                 `json:"name_color"`
+		MarketName      string                 `json:"market_name"`
+		MarketHashName  string                 `json:"market_hash_name"`
+		Commodity       string                 `json:"commodity"`
+		Actions         map[string]*EconAction `json:"actions"`
+		Tags            map[string]*ClassTag   `json:"tags"`
+		Descriptions    map[string]*EconDesc   `json:"descriptions"`
+	}
+	type Response struct {
+		Result map[string]json.RawMessage `json:"result"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if success, ok := response.Result["success"]; !ok || string(success) != "true" {
+		if msg, ok := response.Result["error"]; ok {
+			return nil, fmt.Errorf("asset class info: %s", msg)
+		}
+
+		return nil, errors.New("asset class info: no result")
+	}
+
+	descriptions := make(econDescriptions)
+	for key, raw := range response.Result {
+		// Items without an instance are keyed by class alone.
+		if !strings.Contains(key, "_") {
+			key += "_0"
+		}
+
+		if !classes[key] {
+			continue
+		}
+
+		info := &ClassInfo{}
+		if err = json.Unmarshal(raw, info); err != nil {
+			return nil, err
+		}
+
+		fmt.Sscanf(key, "%d_%d", &info.ClassID, &info.InstanceID)
+		desc := &EconItemDesc{
+			AppID:           appID,
+			ClassID:         info.ClassID,
+			InstanceID:      info.InstanceID,
+			Tradable:        info.Tradable,
+			BackgroundColor: info.BackgroundColor,
+			IconURL:         info.IconURL,
+			IconLargeURL:    info.IconLargeURL,
+			IconDragURL:     info.IconDragURL,
+			Name:            info.Name,
+			NameColor:       info.NameColor,
+			MarketName:      info.MarketName,
+			MarketHashName:  info.MarketHashName,
+			Comodity:        info.Commodity == "1",
+		}
+
+		for n := 0; n < len(info.Actions); n++ {
+			if action, ok := info.Actions[strconv.Itoa(n)]; ok {
+				desc.Actions = append(desc.Actions, action)
+			}
+		}
+
+		for n := 0; n < len(info.Tags); n++ {
+			if tag, ok := info.Tags[strconv.Itoa(n)]; ok {
+				desc.Tags = append(desc.Tags, &EconTag{
+					InternalName:          tag.InternalName,
+					Category:              tag.Category,
+					LocalizedCategoryName: tag.CategoryName,
+					LocalizedTagName:      tag.Name,
+				})
+			}
+		}
+
+		for n := 0; n < len(info.Descriptions); n++ {
+			if d, ok := info.Descriptions[strconv.Itoa(n)]; ok {
+				desc.Descriptions = append(desc.Descriptions, d)
+			}
+		}
+
+		descriptions.add(desc)
+	}
+
+	return descriptions, nil
+}
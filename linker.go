@@ -0,0 +1,202 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LinkState is where an AuthenticatorLinker stands.
+type LinkState int
+
+const (
+	LinkStateNone LinkState = iota
+	// The account has no phone number, add one (see AddPhoneNumber) and Link again.
+	LinkStatePhoneRequired
+	// The account already has a mobile authenticator.
+	LinkStateAlreadyLinked
+	// Secrets were received and persisted, Finalize with the SMS code.
+	LinkStateAwaitingSMSCode
+	// The SMS code was rejected, Finalize again with the right one.
+	LinkStateBadSMSCode
+	// The authenticator is active.
+	LinkStateFinalized
+)
+
+// AddAuthenticator / FinalizeAddAuthenticator statuses.
+const (
+	linkStatusOK                    = 1
+	linkStatusPhoneRequired         = 2
+	linkStatusAlreadyLinked         = 29
+	linkStatusRateLimited           = 84
+	linkStatusTwoFactorCodeMismatch = 88
+	linkStatusBadSMSCode            = 89
+
+	linkMaxFinalizeAttempts = 30
+)
+
+var (
+	ErrLinkNotStarted          = errors.New("authenticator linking was not started")
+	ErrCannotGenerateValidCode = errors.New("unable to generate a twofactor code Steam accepts")
+	ErrEmptyTwoFactorResponse  = errors.New("empty two factor response")
+	ErrNoPersist               = errors.New("authenticator linker has no persist function")
+)
+
+func (state LinkState) String() string {
+	switch state {
+	case LinkStateNone:
+		return "none"
+	case LinkStatePhoneRequired:
+		return "phone required"
+	case LinkStateAlreadyLinked:
+		return "already linked"
+	case LinkStateAwaitingSMSCode:
+		return "awaiting SMS code"
+	case LinkStateBadSMSCode:
+		return "bad SMS code"
+	case LinkStateFinalized:
+		return "finalized"
+	}
+
+	return fmt.Sprintf("LinkState(%d)", int(state))
+}
+
+// AuthenticatorLinker guides adding a mobile authenticator to the account
+// of a logged in session: Link, then Finalize with the SMS code.
+// The secrets are handed to persist before finalizing, and again once
+// the authenticator is active, so they are never lost halfway.
+type AuthenticatorLinker struct {
+	session *Session
+	persist func(account *SteamGuardAccount) error
+	account *SteamGuardAccount
+	state   LinkState
+}
+
+func NewAuthenticatorLinker(session *Session, persist func(account *SteamGuardAccount) error) *AuthenticatorLinker {
+	return &AuthenticatorLinker{
+		session: session,
+		persist: persist,
+	}
+}
+
+func (linker *AuthenticatorLinker) State() LinkState {
+	return linker.state
+}
+
+// Account returns the secrets received by Link, nil before that.
+func (linker *AuthenticatorLinker) Account() *SteamGuardAccount {
+	return linker.account
+}
+
+func (linker *AuthenticatorLinker) Link() (LinkState, error) {
+	return linker.LinkContext(context.Background())
+}
+
+func (linker *AuthenticatorLinker) LinkContext(ctx context.Context) (LinkState, error) {
+	if linker.persist == nil {
+		return linker.state, ErrNoPersist
+	}
+
+	info, err := linker.session.EnableTwoFactorContext(ctx)
+	if err != nil {
+		return linker.state, err
+	}
+
+	if info == nil {
+		return linker.state, ErrEmptyTwoFactorResponse
+	}
+
+	switch info.Status {
+	case linkStatusOK:
+	case linkStatusPhoneRequired:
+		linker.state = LinkStatePhoneRequired
+		return linker.state, nil
+	case linkStatusAlreadyLinked:
+		linker.state = LinkStateAlreadyLinked
+		return linker.state, nil
+	case linkStatusRateLimited:
+		return linker.state, &RateLimitedError{RetryAfter: loginRateLimitWait}
+	default:
+		return linker.state, fmt.Errorf("add authenticator: status %d", info.Status)
+	}
+
+	linker.account = NewSteamGuardAccount(info.AccountName, linker.session.deviceID, info)
+	linker.account.SetSession(linker.session)
+	linker.state = LinkStateAwaitingSMSCode
+
+	if err = linker.persist(linker.account); err != nil {
+		return linker.state, err
+	}
+
+	return linker.state, nil
+}
+
+func (linker *AuthenticatorLinker) Finalize(smsCode string) (LinkState, error) {
+	return linker.FinalizeContext(context.Background(), smsCode)
+}
+
+// FinalizeContext activates the authenticator with @smsCode, it keeps
+// sending codes while Steam wants more or rejects them for time drift.
+func (linker *AuthenticatorLinker) FinalizeContext(ctx context.Context, smsCode string) (LinkState, error) {
+	if linker.account == nil {
+		return linker.state, ErrLinkNotStarted
+	}
+
+	if linker.persist == nil {
+		return linker.state, ErrNoPersist
+	}
+
+	var drift time.Duration
+	for attempt := 0; attempt < linkMaxFinalizeAttempts; attempt++ {
+		now, err := linker.session.now(ctx)
+		if err != nil {
+			return linker.state, err
+		}
+
+		code, err := linker.account.GenerateTwoFactorCode(now.Add(drift).Unix())
+		if err != nil {
+			return linker.state, err
+		}
+
+		info, err := linker.session.FinalizeTwoFactorContext(ctx, code, smsCode)
+		if err != nil {
+			return linker.state, err
+		}
+
+		if info == nil {
+			return linker.state, ErrEmptyTwoFactorResponse
+		}
+
+		switch {
+		case info.Status == linkStatusBadSMSCode:
+			linker.state = LinkStateBadSMSCode
+			return linker.state, nil
+		case info.Status == linkStatusTwoFactorCodeMismatch:
+			// Our clock is off, generate the next code for Steam's time,
+			// without it the same code would only be sent again.
+			if info.ServerTime == 0 {
+				return linker.state, ErrCannotGenerateValidCode
+			}
+
+			serverDrift := time.Unix(int64(info.ServerTime), 0).Sub(now)
+			if serverDrift == drift {
+				return linker.state, ErrCannotGenerateValidCode
+			}
+
+			drift = serverDrift
+			continue
+		case !info.Success:
+			return linker.state, fmt.Errorf("finalize authenticator: status %d", info.Status)
+		case info.WantMore:
+			drift += 30 * time.Second
+			continue
+		}
+
+		linker.account.FullyEnrolled = true
+		linker.state = LinkStateFinalized
+		return linker.state, linker.persist(linker.account)
+	}
+
+	return linker.state, ErrCannotGenerateValidCode
+}
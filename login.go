@@ -1,14 +1,11 @@
 package steam
 
 import (
+	"context"
 	"crypto/md5"
-	"crypto/rand"
-	"crypto/rsa"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/big"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
@@ -33,29 +30,75 @@ type OAuth struct {
 }
 
 type LoginSession struct {
-	Success           bool   `json:"success"`
-	LoginComplete     bool   `json:"login_complete"`
-	RequiresTwoFactor bool   `json:"requires_twofactor"`
-	Message           string `json:"message"`
-	RedirectURI       string `json:"redirect_uri"`
-	OAuth             OAuth  `json:"transfer_parameters"`
+	Success           bool        `json:"success"`
+	LoginComplete     bool        `json:"login_complete"`
+	RequiresTwoFactor bool        `json:"requires_twofactor"`
+	EmailAuthNeeded   bool        `json:"emailauth_needed"`
+	EmailDomain       string      `json:"emaildomain"`
+	EmailSteamID      string      `json:"emailsteamid"`
+	CaptchaNeeded     bool        `json:"captcha_needed"`
+	CaptchaGID        json.Number `json:"captcha_gid"`
+	Message           string      `json:"message"`
+	RedirectURI       string      `json:"redirect_uri"`
+	OAuth             OAuth       `json:"transfer_parameters"`
+}
+
+// loginAttempt keeps what is needed to resume a login that stopped
+// on an email code or captcha, only one of auth and legacy is set.
+type loginAttempt struct {
+	accountName   string
+	password      string
+	twoFactorCode string
+	auth          *AuthSession
+	legacy        *LoginResponse
+	emailAuth     string
+	emailSteamID  string
+	captchaGID    string
+	captchaText   string
+}
+
+// EmailCodeRequiredError is returned when Steam Guard sent a code to the
+// account email, resume with ResumeLoginEmailCode.
+type EmailCodeRequiredError struct {
+	EmailDomain string
+}
+
+func (err *EmailCodeRequiredError) Error() string {
+	return fmt.Sprintf("steam guard code was sent to email at %s", err.EmailDomain)
+}
+
+// CaptchaRequiredError is returned by LegacyLogin when a captcha has to be
+// solved, resume with ResumeLoginCaptcha.
+type CaptchaRequiredError struct {
+	CaptchaGID string
+	ImageURL   string
+}
+
+func (err *CaptchaRequiredError) Error() string {
+	return fmt.Sprintf("captcha required: %s", err.ImageURL)
 }
 
 type Session struct {
-	client      *http.Client
-	oauth       OAuth
-	sessionID   string
-	apiKey      string
-	deviceID    string
-	umqID       string
-	chatMessage int
-	language    string
-	debug       bool
+	client       *http.Client
+	oauth        OAuth
+	refreshToken string
+	sessionID    string
+	apiKey       string
+	deviceID     string
+	umqID        string
+	chatMessage  int
+	language     string
+	clock        Clock
+	debug        bool
+
+	pendingLogin *loginAttempt
 }
 
 const (
 	httpBaseUrl  = "https://steamcommunity.com"
 	httpLoginUrl = "https://steamcommunity.com/login"
+
+	httpRenderCaptchaURL = "https://steamcommunity.com/login/rendercaptcha/?gid="
 	//httpXRequestedWithValue = "com.valvesoftware.android.steam.community"
 	//httpAcceptValue         = "text/javascript, text/html, application/xml, text/xml, */*"
 	//httpUserAgentValue      = "Mozilla/5.0 (Linux; U; Android 4.1.1; en-us; Google Nexus 4 - 4.1.1 - API 16 - 768x1280 Build/JRO03S) AppleWebKit/534.30 (KHTML, like Gecko) Version/4.0 Mobile Safari/534.30"
@@ -65,39 +108,118 @@ const (
 var (
 	ErrEmptySessionID  = errors.New("sessionid is empty")
 	ErrInvalidUsername = errors.New("invalid username")
-	ErrNeedTwoFactor   = errors.New("invalid twofactor code")
+	ErrNeedTwoFactor   = errors.New("twofactor code required")
+	ErrNoPendingLogin  = errors.New("no login attempt to resume")
+	ErrNoCaptcha       = errors.New("login attempt does not use captcha")
+	ErrNotLoggedIn     = errors.New("not logged in")
+
+	ErrInvalidPassword      = errors.New("invalid account name or password")
+	ErrTooManyLoginFailures = errors.New("too many login failures")
+	ErrAccountLocked        = errors.New("account is locked")
+	ErrInvalidTwoFactorCode = errors.New("invalid twofactor code")
+	ErrInvalidEmailCode     = errors.New("invalid email code")
+	ErrRSATimestampExpired  = errors.New("RSA timestamp expired")
 )
 
-func (session *Session) proceedDirectLogin(response *LoginResponse, accountName, password, twoFactorCode string) error {
-	var n big.Int
-	n.SetString(response.PublicKeyMod, 16)
+// Steam only says to wait "a while" after too many failures.
+const loginRateLimitWait = 30 * time.Minute
 
-	exp, err := strconv.ParseInt(response.PublicKeyExp, 16, 32)
-	if err != nil {
+// EResult values login failures are reported with.
+const (
+	eresultInvalidPassword            = 5
+	eresultExpired                    = 27
+	eresultDuplicateRequest           = 29
+	eresultInvalidLoginAuthCode       = 65
+	eresultAccountLockedDown          = 73
+	eresultRateLimitExceeded          = 84
+	eresultAccountLoginDeniedThrottle = 87
+	eresultTwoFactorCodeMismatch      = 88
+)
+
+// RateLimitedError is returned when Steam refuses logins for a while,
+// it matches ErrTooManyLoginFailures with errors.Is.
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (err *RateLimitedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyLoginFailures, err.RetryAfter)
+}
+
+func (err *RateLimitedError) Is(target error) bool {
+	return target == ErrTooManyLoginFailures
+}
+
+// loginError maps a failed IAuthenticationService call to the login errors above.
+func loginError(err error) error {
+	var eresult *EResultError
+	if !errors.As(err, &eresult) {
 		return err
 	}
 
-	pub := rsa.PublicKey{N: &n, E: int(exp)}
-	rsaOut, err := rsa.EncryptPKCS1v15(rand.Reader, &pub, []byte(password))
+	switch eresult.Result {
+	case eresultInvalidPassword:
+		return ErrInvalidPassword
+	case eresultExpired:
+		return ErrRSATimestampExpired
+	case eresultInvalidLoginAuthCode:
+		return ErrInvalidEmailCode
+	case eresultAccountLockedDown:
+		return ErrAccountLocked
+	case eresultRateLimitExceeded, eresultAccountLoginDeniedThrottle:
+		return &RateLimitedError{RetryAfter: loginRateLimitWait}
+	case eresultTwoFactorCodeMismatch:
+		return ErrInvalidTwoFactorCode
+	}
+
+	return err
+}
+
+// legacyLoginError maps the message of a failed dologin to the login errors above.
+func legacyLoginError(message string) error {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "password that you have entered is incorrect"):
+		return ErrInvalidPassword
+	case strings.Contains(lower, "too many login failures"):
+		return &RateLimitedError{RetryAfter: loginRateLimitWait}
+	case strings.Contains(lower, "locked"):
+		return ErrAccountLocked
+	case strings.Contains(lower, "rsa") || strings.Contains(lower, "expired"):
+		return ErrRSATimestampExpired
+	}
+
+	return errors.New(message)
+}
+
+func (session *Session) proceedDirectLogin(ctx context.Context, attempt *loginAttempt) error {
+	response := attempt.legacy
+	encryptedPassword, err := encryptPassword(response.PublicKeyMod, response.PublicKeyExp, attempt.password)
 	if err != nil {
 		return err
 	}
 
+	captchaGID := attempt.captchaGID
+	if len(captchaGID) == 0 {
+		captchaGID = "-1"
+	}
+
 	reqData := url.Values{
-		"captcha_text":      {""},
-		"captchagid":        {"-1"},
-		"emailauth":         {""},
-		"emailsteamid":      {""},
-		"username":          {accountName},
-		"password":          {base64.StdEncoding.EncodeToString(rsaOut)},
+		"captcha_text":      {attempt.captchaText},
+		"captchagid":        {captchaGID},
+		"emailauth":         {attempt.emailAuth},
+		"emailsteamid":      {attempt.emailSteamID},
+		"username":          {attempt.accountName},
+		"password":          {encryptedPassword},
 		"remember_login":    {"true"},
 		"rsatimestamp":      {response.Timestamp},
-		"twofactorcode":     {twoFactorCode},
+		"twofactorcode":     {attempt.twoFactorCode},
 		"donotcache":        {strconv.FormatInt(time.Now().Unix()*1000, 10)},
 		"loginfriendlyname": {""},
 	}.Encode()
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		"https://steamcommunity.com/login/dologin",
 		strings.NewReader(reqData),
@@ -128,25 +250,39 @@ func (session *Session) proceedDirectLogin(response *LoginResponse, accountName,
 
 	if !loginSession.Success {
 		if loginSession.RequiresTwoFactor {
+			if len(attempt.twoFactorCode) != 0 {
+				return ErrInvalidTwoFactorCode
+			}
+
 			return ErrNeedTwoFactor
 		}
 
-		return errors.New(loginSession.Message)
-	}
+		if loginSession.CaptchaNeeded {
+			attempt.captchaGID = loginSession.CaptchaGID.String()
+			session.pendingLogin = attempt
+			return &CaptchaRequiredError{
+				CaptchaGID: attempt.captchaGID,
+				ImageURL:   httpRenderCaptchaURL + attempt.captchaGID,
+			}
+		}
 
-	session.oauth = loginSession.OAuth
+		if loginSession.EmailAuthNeeded {
+			attempt.emailSteamID = loginSession.EmailSteamID
+			session.pendingLogin = attempt
+			if len(attempt.emailAuth) != 0 {
+				return ErrInvalidEmailCode
+			}
 
-	steamUrl, _ := url.Parse(httpBaseUrl)
-	cookies := session.client.Jar.Cookies(steamUrl)
-	for _, cookie := range cookies {
-		if cookie.Name == "sessionid" {
-			session.sessionID = cookie.Value
-			break
+			return &EmailCodeRequiredError{EmailDomain: loginSession.EmailDomain}
 		}
+
+		return legacyLoginError(loginSession.Message)
 	}
 
-	if session.sessionID == "" {
-		return ErrEmptySessionID
+	session.pendingLogin = nil
+	session.oauth = loginSession.OAuth
+	if err := session.loadSessionID(); err != nil {
+		return err
 	}
 
 	//randomBytes := make([]byte, 6)
@@ -167,11 +303,7 @@ func (session *Session) proceedDirectLogin(response *LoginResponse, accountName,
 	//	}
 	//}
 
-	sum := md5.Sum([]byte(accountName + password))
-	session.deviceID = fmt.Sprintf(
-		"android:%x-%x-%x-%x-%x",
-		sum[:2], sum[2:4], sum[4:6], sum[6:8], sum[8:10],
-	)
+	session.deviceID = generateDeviceID(attempt.accountName + attempt.password)
 
 	//cookies = append(cookies, &http.Cookie{
 	//	Name:  "sessionid",
@@ -181,13 +313,14 @@ func (session *Session) proceedDirectLogin(response *LoginResponse, accountName,
 	return nil
 }
 
-func (session *Session) makeLoginRequest(accountName string) (*LoginResponse, error) {
+func (session *Session) makeLoginRequest(ctx context.Context, accountName string) (*LoginResponse, error) {
 	reqData := url.Values{
 		"username":   {accountName},
 		"donotcache": {strconv.FormatInt(time.Now().Unix()*1000, 10)},
 	}.Encode()
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		"https://steamcommunity.com/login/getrsakey",
 		strings.NewReader(reqData),
@@ -225,8 +358,9 @@ func (session *Session) makeLoginRequest(accountName string) (*LoginResponse, er
 	return &response, nil
 }
 
-func (session *Session) setupCookieJar() error {
-	req, err := http.NewRequest(
+func (session *Session) setupCookieJar(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		httpLoginUrl,
 		nil,
@@ -269,47 +403,329 @@ func (session *Session) setupCookieJar() error {
 	return nil
 }
 
-// LoginTwoFactorCode logs in with the @twoFactorCode provided,
-// note that in the case of having shared secret known, then it's better to
-// use Login() because it's more accurate.
-// Note: You can provide an empty two factor code if two factor authentication is not
-// enabled on the account provided.
-func (session *Session) LoginTwoFactorCode(accountName, password, twoFactorCode string) error {
-	err := session.setupCookieJar()
+func (session *Session) get(ctx context.Context, getURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, getURL, nil)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	return session.client.Do(req)
+}
+
+func (session *Session) postForm(ctx context.Context, postURL string, data url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return session.client.Do(req)
+}
+
+// isLoginURL reports whether Steam sent us to its login page.
+func isLoginURL(u *url.URL) bool {
+	return strings.HasPrefix(u.Path, "/login")
+}
+
+// checkLoggedIn returns ErrNotLoggedIn if @resp was redirected to
+// the login page, which is what Steam does once cookies expire.
+func checkLoggedIn(resp *http.Response) error {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return ErrNotLoggedIn
+	}
+
+	if resp.Request != nil && isLoginURL(resp.Request.URL) {
+		return ErrNotLoggedIn
+	}
+
+	return nil
+}
+
+func (session *Session) loadSessionID() error {
+	steamUrl, _ := url.Parse(httpBaseUrl)
+	for _, cookie := range session.client.Jar.Cookies(steamUrl) {
+		if cookie.Name == "sessionid" {
+			session.sessionID = cookie.Value
+			return nil
+		}
+	}
+
+	return ErrEmptySessionID
+}
+
+func (session *Session) loginSecureCookie() string {
+	steamUrl, _ := url.Parse(httpBaseUrl)
+	for _, cookie := range session.client.Jar.Cookies(steamUrl) {
+		if cookie.Name == "steamLoginSecure" {
+			return cookie.Value
+		}
 	}
 
-	response, err := session.makeLoginRequest(accountName)
+	return ""
+}
+
+func generateDeviceID(seed string) string {
+	sum := md5.Sum([]byte(seed))
+	return fmt.Sprintf(
+		"android:%x-%x-%x-%x-%x",
+		sum[:2], sum[2:4], sum[4:6], sum[6:8], sum[8:10],
+	)
+}
+
+// beginCredentialsLogin starts an IAuthenticationService session for
+// @accountName, the Steam Guard step is left to the caller.
+func (session *Session) beginCredentialsLogin(ctx context.Context, accountName, password string) (*AuthSession, error) {
+	if err := session.setupCookieJar(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := session.loadSessionID(); err != nil {
+		return nil, err
+	}
+
+	key, err := session.getPasswordRSAPublicKey(ctx, accountName)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedPassword, err := encryptPassword(key.PublicKeyMod, key.PublicKeyExp, password)
 	if err != nil {
+		return nil, err
+	}
+
+	return session.beginAuthSessionViaCredentials(ctx, accountName, encryptedPassword, key.Timestamp)
+}
+
+// completeLogin waits for @auth to be approved and turns the resulting
+// tokens into web cookies.
+func (session *Session) completeLogin(ctx context.Context, auth *AuthSession, timeout time.Duration, onStatus func(*AuthSessionStatus)) (*AuthSessionStatus, error) {
+	status, err := session.waitAuthSessionStatus(ctx, auth, timeout, onStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	steamID, err := session.finalizeLogin(ctx, status.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = session.loadSessionID(); err != nil {
+		return nil, err
+	}
+
+	session.oauth = OAuth{
+		SteamID:     steamID,
+		Token:       status.AccessToken,
+		LoginSecure: session.loginSecureCookie(),
+	}
+	session.refreshToken = status.RefreshToken
+
+	return status, nil
+}
+
+func (session *Session) loginWithCredentials(ctx context.Context, accountName, password string, twoFactorCode func() (string, error)) error {
+	session.pendingLogin = nil
+	auth, err := session.beginCredentialsLogin(ctx, accountName, password)
+	if err != nil {
+		return err
+	}
+
+	attempt := &loginAttempt{
+		accountName: accountName,
+		password:    password,
+		auth:        auth,
+	}
+
+	switch {
+	case auth.Allows(AuthGuardTypeNone):
+	case auth.Allows(AuthGuardTypeDeviceCode):
+		code, err := twoFactorCode()
+		if err != nil {
+			return err
+		}
+
+		if len(code) == 0 {
+			return ErrNeedTwoFactor
+		}
+
+		if err = session.updateAuthSessionWithSteamGuardCode(ctx, auth, code, AuthGuardTypeDeviceCode); err != nil {
+			return err
+		}
+	case auth.Allows(AuthGuardTypeEmailCode):
+		session.pendingLogin = attempt
+		return &EmailCodeRequiredError{EmailDomain: auth.associatedMessage(AuthGuardTypeEmailCode)}
+	default:
+		return ErrUnsupportedAuthConfirmation
+	}
+
+	return session.completeCredentialsLogin(ctx, attempt)
+}
+
+func (session *Session) completeCredentialsLogin(ctx context.Context, attempt *loginAttempt) error {
+	if _, err := session.completeLogin(ctx, attempt.auth, authPollTimeout, nil); err != nil {
 		return err
 	}
 
-	return session.proceedDirectLogin(response, accountName, password, twoFactorCode)
+	session.pendingLogin = nil
+	session.deviceID = generateDeviceID(attempt.accountName + attempt.password)
+	return nil
+}
+
+// ResumeLoginEmailCode continues the login attempt which returned
+// EmailCodeRequiredError with the @code received by email.
+func (session *Session) ResumeLoginEmailCode(code string) error {
+	return session.ResumeLoginEmailCodeContext(context.Background(), code)
+}
+
+func (session *Session) ResumeLoginEmailCodeContext(ctx context.Context, code string) error {
+	attempt := session.pendingLogin
+	if attempt == nil {
+		return ErrNoPendingLogin
+	}
+
+	if attempt.legacy != nil {
+		attempt.emailAuth = code
+		return session.proceedDirectLogin(ctx, attempt)
+	}
+
+	if err := session.updateAuthSessionWithSteamGuardCode(ctx, attempt.auth, code, AuthGuardTypeEmailCode); err != nil {
+		return err
+	}
+
+	return session.completeCredentialsLogin(ctx, attempt)
+}
+
+// ResumeLoginCaptcha continues the LegacyLogin attempt which returned
+// CaptchaRequiredError with the @captchaText read from its image.
+func (session *Session) ResumeLoginCaptcha(captchaText string) error {
+	return session.ResumeLoginCaptchaContext(context.Background(), captchaText)
+}
+
+func (session *Session) ResumeLoginCaptchaContext(ctx context.Context, captchaText string) error {
+	attempt := session.pendingLogin
+	if attempt == nil {
+		return ErrNoPendingLogin
+	}
+
+	if attempt.legacy == nil {
+		return ErrNoCaptcha
+	}
+
+	attempt.captchaText = captchaText
+	return session.proceedDirectLogin(ctx, attempt)
+}
+
+// LoginTwoFactorCode logs in with the @twoFactorCode provided,
+// note that in the case of having shared secret known, then it's better to
+// use Login() because it's more accurate.
+// Note: You can provide an empty two factor code if two factor authentication is not
+// enabled on the account provided.
+func (session *Session) LoginTwoFactorCode(accountName, password, twoFactorCode string) error {
+	return session.LoginTwoFactorCodeContext(context.Background(), accountName, password, twoFactorCode)
 }
 
-// Login requests log in information first, then generates two factor code, and proceeds
+func (session *Session) LoginTwoFactorCodeContext(ctx context.Context, accountName, password, twoFactorCode string) error {
+	return session.loginWithCredentials(ctx, accountName, password, func() (string, error) {
+		return twoFactorCode, nil
+	})
+}
+
+// Login begins an auth session first, then generates two factor code, and proceeds
 // to do the actual login, this provides a better chance that the code generated will work
 // because of the slowness of the API.
+// The code is generated for the session clock (see SetClock) shifted by @timeOffset.
 func (session *Session) Login(accountName, password, sharedSecret string, timeOffset time.Duration) error {
-	err := session.setupCookieJar()
-	if err != nil {
+	return session.LoginContext(context.Background(), accountName, password, sharedSecret, timeOffset)
+}
+
+func (session *Session) LoginContext(ctx context.Context, accountName, password, sharedSecret string, timeOffset time.Duration) error {
+	return session.loginWithCredentials(ctx, accountName, password, func() (string, error) {
+		if len(sharedSecret) == 0 {
+			return "", nil
+		}
+
+		now, err := session.now(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		return GenerateTwoFactorCode(sharedSecret, now.Add(timeOffset).Unix())
+	})
+}
+
+// BeginQRLogin starts a QR code login, render the returned ChallengeURL
+// and pass the session to FinishQRLogin.
+func (session *Session) BeginQRLogin() (*QRAuthSession, error) {
+	return session.BeginQRLoginContext(context.Background())
+}
+
+func (session *Session) BeginQRLoginContext(ctx context.Context) (*QRAuthSession, error) {
+	if err := session.setupCookieJar(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := session.loadSessionID(); err != nil {
+		return nil, err
+	}
+
+	return session.beginAuthSessionViaQR(ctx)
+}
+
+// FinishQRLogin polls @qr until the code is scanned and approved, then
+// proceeds to log in just like Login does.
+// Every poll result is sent to @updates (may be nil), when Steam rotates the
+// challenge, NewChallengeURL is set and @qr.ChallengeURL is updated, so the
+// code has to be rendered again.
+func (session *Session) FinishQRLogin(qr *QRAuthSession, updates chan<- *AuthSessionStatus) error {
+	return session.FinishQRLoginContext(context.Background(), qr, updates)
+}
+
+func (session *Session) FinishQRLoginContext(ctx context.Context, qr *QRAuthSession, updates chan<- *AuthSessionStatus) error {
+	onStatus := func(status *AuthSessionStatus) {
+		if len(status.NewChallengeURL) != 0 {
+			qr.ChallengeURL = status.NewChallengeURL
+		}
+
+		if updates != nil {
+			select {
+			case updates <- status:
+			case <-ctx.Done():
+			}
+		}
+	}
+
+	if _, err := session.completeLogin(ctx, &qr.AuthSession, qrPollTimeout, onStatus); err != nil {
 		return err
 	}
 
-	response, err := session.makeLoginRequest(accountName)
+	session.deviceID = generateDeviceID(session.oauth.SteamID.ToString())
+	return nil
+}
+
+// LegacyLogin logs in through the retired /login/dologin endpoint, it is
+// only kept for accounts Steam still serves there.
+func (session *Session) LegacyLogin(accountName, password, twoFactorCode string) error {
+	return session.LegacyLoginContext(context.Background(), accountName, password, twoFactorCode)
+}
+
+func (session *Session) LegacyLoginContext(ctx context.Context, accountName, password, twoFactorCode string) error {
+	session.pendingLogin = nil
+	err := session.setupCookieJar(ctx)
 	if err != nil {
 		return err
 	}
 
-	var twoFactorCode string
-	if len(sharedSecret) != 0 {
-		if twoFactorCode, err = GenerateTwoFactorCode(sharedSecret, time.Now().Add(timeOffset).Unix()); err != nil {
-			return err
-		}
+	response, err := session.makeLoginRequest(ctx, accountName)
+	if err != nil {
+		return err
 	}
 
-	return session.proceedDirectLogin(response, accountName, password, twoFactorCode)
+	return session.proceedDirectLogin(ctx, &loginAttempt{
+		accountName:   accountName,
+		password:      password,
+		twoFactorCode: twoFactorCode,
+		legacy:        response,
+	})
 }
 
 func (session *Session) GetSteamID() SteamID {
@@ -0,0 +1,514 @@
+package steam
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// SteamDesktopAuthenticator encryption parameters.
+const (
+	maFileKeySize          = 32
+	maFileSaltSize         = 8
+	maFilePBKDF2Iterations = 50000
+
+	manifestFilename = "manifest.json"
+	maFileExtension  = ".maFile"
+	backupExtension  = ".bak"
+)
+
+var (
+	ErrInvalidPasscode      = errors.New("invalid passcode")
+	ErrManifestNotEncrypted = errors.New("manifest is not encrypted")
+	ErrManifestEntryMissing = errors.New("no manifest entry for account")
+)
+
+type ManifestEntry struct {
+	EncryptionIV   string  `json:"encryption_iv"`
+	EncryptionSalt string  `json:"encryption_salt"`
+	Filename       string  `json:"filename"`
+	SteamID        SteamID `json:"steamid"`
+}
+
+// Manifest is the manifest.json SteamDesktopAuthenticator keeps next to
+// its maFiles, it records the salt and IV every maFile is encrypted with.
+type Manifest struct {
+	Encrypted                     bool             `json:"encrypted"`
+	FirstRun                      bool             `json:"first_run"`
+	Entries                       []*ManifestEntry `json:"entries"`
+	PeriodicChecking              bool             `json:"periodic_checking"`
+	PeriodicCheckingInterval      int              `json:"periodic_checking_interval"`
+	PeriodicCheckingCheckAll      bool             `json:"periodic_checking_checkall"`
+	AutoConfirmMarketTransactions bool             `json:"auto_confirm_market_transactions"`
+	AutoConfirmTrades             bool             `json:"auto_confirm_trades"`
+
+	dir string
+}
+
+// pbkdf2SHA1 derives a key the way .NET Rfc2898DeriveBytes does.
+func pbkdf2SHA1(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha1.New, password)
+	hashLen := prf.Size()
+	numBlocks := (keyLen + hashLen - 1) / hashLen
+
+	var counter [4]byte
+	key := make([]byte, 0, numBlocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= numBlocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Write(counter[:])
+		key = prf.Sum(key)
+
+		t := key[len(key)-hashLen:]
+		copy(u, t)
+		for n := 2; n <= iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for i := range u {
+				t[i] ^= u[i]
+			}
+		}
+	}
+
+	return key[:keyLen]
+}
+
+func maFileCipher(passcode, salt string) (cipher.Block, error) {
+	rawSalt, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return aes.NewCipher(pbkdf2SHA1([]byte(passcode), rawSalt, maFilePBKDF2Iterations, maFileKeySize))
+}
+
+// EncryptMaFile encrypts @data with @passcode using a fresh salt and IV,
+// the result is base64 text as SteamDesktopAuthenticator writes it.
+func EncryptMaFile(data []byte, passcode string) (encrypted []byte, salt, iv string, err error) {
+	rawSalt := make([]byte, maFileSaltSize)
+	if _, err = rand.Read(rawSalt); err != nil {
+		return nil, "", "", err
+	}
+
+	rawIV := make([]byte, aes.BlockSize)
+	if _, err = rand.Read(rawIV); err != nil {
+		return nil, "", "", err
+	}
+
+	salt = base64.StdEncoding.EncodeToString(rawSalt)
+	iv = base64.StdEncoding.EncodeToString(rawIV)
+
+	block, err := maFileCipher(passcode, salt)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	plain := append(append([]byte{}, data...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	out := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, rawIV).CryptBlocks(out, plain)
+
+	encrypted = make([]byte, base64.StdEncoding.EncodedLen(len(out)))
+	base64.StdEncoding.Encode(encrypted, out)
+	return encrypted, salt, iv, nil
+}
+
+// DecryptMaFile reverses EncryptMaFile, a wrong @passcode gives ErrInvalidPasscode.
+func DecryptMaFile(encrypted []byte, passcode, salt, iv string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encrypted)))
+	if err != nil {
+		return nil, err
+	}
+
+	rawIV, err := base64.StdEncoding.DecodeString(iv)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rawIV) != aes.BlockSize || len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, ErrInvalidPasscode
+	}
+
+	block, err := maFileCipher(passcode, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, rawIV).CryptBlocks(plain, data)
+
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, ErrInvalidPasscode
+	}
+
+	for _, b := range plain[len(plain)-padding:] {
+		if int(b) != padding {
+			return nil, ErrInvalidPasscode
+		}
+	}
+
+	return plain[:len(plain)-padding], nil
+}
+
+// LoadManifest reads manifest.json from the maFiles directory @dir.
+func LoadManifest(dir string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, manifestFilename))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, err
+	}
+
+	manifest.dir = dir
+	return manifest, nil
+}
+
+// NewManifest returns an empty manifest for @dir, nothing is written until Save.
+func NewManifest(dir string) *Manifest {
+	return &Manifest{
+		Entries:                  []*ManifestEntry{},
+		PeriodicCheckingInterval: 5,
+		dir:                      dir,
+	}
+}
+
+// Save replaces manifest.json atomically.
+func (manifest *Manifest) Save() error {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := writeTempFile(manifest.dir, data)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp, filepath.Join(manifest.dir, manifestFilename)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
+
+// writeTempFile writes @data to a new file in @dir readable by the owner only,
+// so it can be renamed over the file it replaces.
+func writeTempFile(dir string, data []byte) (string, error) {
+	file, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = file.Write(data); err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+func (manifest *Manifest) entry(sid SteamID) *ManifestEntry {
+	for _, entry := range manifest.Entries {
+		if entry.SteamID == sid {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// LoadAccount reads and, if the manifest is encrypted, decrypts the maFile
+// of @entry in memory, @passcode is ignored for plain manifests.
+func (manifest *Manifest) LoadAccount(entry *ManifestEntry, passcode string) (*SteamGuardAccount, error) {
+	data, err := ioutil.ReadFile(filepath.Join(manifest.dir, entry.Filename))
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Encrypted {
+		if data, err = DecryptMaFile(data, passcode, entry.EncryptionSalt, entry.EncryptionIV); err != nil {
+			return nil, err
+		}
+	}
+
+	account, err := ParseSteamGuardAccount(data)
+	if err != nil {
+		if manifest.Encrypted {
+			return nil, ErrInvalidPasscode
+		}
+
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// LoadAccountBySteamID is LoadAccount for the entry of @sid.
+func (manifest *Manifest) LoadAccountBySteamID(sid SteamID, passcode string) (*SteamGuardAccount, error) {
+	entry := manifest.entry(sid)
+	if entry == nil {
+		return nil, ErrManifestEntryMissing
+	}
+
+	return manifest.LoadAccount(entry, passcode)
+}
+
+func (manifest *Manifest) LoadAccounts(passcode string) ([]*SteamGuardAccount, error) {
+	accounts := make([]*SteamGuardAccount, 0, len(manifest.Entries))
+	for _, entry := range manifest.Entries {
+		account, err := manifest.LoadAccount(entry, passcode)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// VerifyPasscode checks @passcode against every encrypted maFile.
+func (manifest *Manifest) VerifyPasscode(passcode string) error {
+	if !manifest.Encrypted {
+		return ErrManifestNotEncrypted
+	}
+
+	_, err := manifest.LoadAccounts(passcode)
+	return err
+}
+
+// pendingMaFile is a maFile written to a temporary path, with the salt and
+// IV it was encrypted with, waiting for manifest.json to record them.
+type pendingMaFile struct {
+	entry          *ManifestEntry
+	tmp            string
+	encryptionSalt string
+	encryptionIV   string
+}
+
+func (manifest *Manifest) prepareAccount(entry *ManifestEntry, account *SteamGuardAccount, passcode string) (*pendingMaFile, error) {
+	data, err := account.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &pendingMaFile{entry: entry}
+	if manifest.Encrypted {
+		if data, pending.encryptionSalt, pending.encryptionIV, err = EncryptMaFile(data, passcode); err != nil {
+			return nil, err
+		}
+	}
+
+	if pending.tmp, err = writeTempFile(manifest.dir, data); err != nil {
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+// commitAccounts records the salts and IVs of @pending in manifest.json and
+// moves the maFiles in place. The previous manifest.json and maFiles are kept
+// as ".bak" copies until every file is in place and put back on failure, so
+// after a crash the ".bak" files still hold a state the old passcode opens.
+func (manifest *Manifest) commitAccounts(pending []*pendingMaFile) error {
+	type encryption struct {
+		salt string
+		iv   string
+	}
+
+	manifestPath := filepath.Join(manifest.dir, manifestFilename)
+	paths := make([]string, len(pending))
+	backedUp := make([]bool, len(pending))
+	previous := make([]encryption, len(pending))
+	var manifestBackedUp, manifestSaved bool
+	placed := 0
+
+	rollback := func(err error) error {
+		for k := range pending {
+			switch {
+			case k < placed && backedUp[k]:
+				os.Rename(paths[k]+backupExtension, paths[k])
+			case k < placed:
+				os.Remove(paths[k])
+			case backedUp[k]:
+				os.Remove(paths[k] + backupExtension)
+			}
+
+			if k >= placed {
+				os.Remove(pending[k].tmp)
+			}
+
+			pending[k].entry.EncryptionSalt = previous[k].salt
+			pending[k].entry.EncryptionIV = previous[k].iv
+		}
+
+		if manifestBackedUp {
+			os.Rename(manifestPath+backupExtension, manifestPath)
+		} else if manifestSaved {
+			os.Remove(manifestPath)
+		}
+
+		return err
+	}
+
+	for k, p := range pending {
+		paths[k] = filepath.Join(manifest.dir, p.entry.Filename)
+		previous[k] = encryption{p.entry.EncryptionSalt, p.entry.EncryptionIV}
+	}
+
+	var err error
+	if manifestBackedUp, err = backupFile(manifestPath); err != nil {
+		return rollback(err)
+	}
+
+	for k := range pending {
+		if backedUp[k], err = backupFile(paths[k]); err != nil {
+			return rollback(err)
+		}
+	}
+
+	for _, p := range pending {
+		p.entry.EncryptionSalt = p.encryptionSalt
+		p.entry.EncryptionIV = p.encryptionIV
+	}
+
+	if err = manifest.Save(); err != nil {
+		return rollback(err)
+	}
+	manifestSaved = true
+
+	for ; placed < len(pending); placed++ {
+		if err = os.Rename(pending[placed].tmp, paths[placed]); err != nil {
+			return rollback(err)
+		}
+	}
+
+	// manifest.json.bak goes first, maFile backups are useless without it.
+	os.Remove(manifestPath + backupExtension)
+	for k, path := range paths {
+		if backedUp[k] {
+			os.Remove(path + backupExtension)
+		}
+	}
+
+	return nil
+}
+
+// backupFile copies @path to "<path>.bak", it tells if there was anything to copy.
+func backupFile(path string) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	tmp, err := writeTempFile(filepath.Dir(path), data)
+	if err != nil {
+		return false, err
+	}
+
+	if err = os.Rename(tmp, path+backupExtension); err != nil {
+		os.Remove(tmp)
+		return false, err
+	}
+
+	return true, nil
+}
+
+func removePending(pending []*pendingMaFile) {
+	for _, p := range pending {
+		os.Remove(p.tmp)
+	}
+}
+
+// SaveAccount writes @account as "<steamid>.maFile", encrypted with
+// @passcode if the manifest is, and records it in manifest.json.
+func (manifest *Manifest) SaveAccount(account *SteamGuardAccount, passcode string) error {
+	if account.Session == nil {
+		return ErrNoSteamGuardSession
+	}
+
+	entry := manifest.entry(account.Session.SteamID)
+	added := entry == nil
+	if added {
+		entry = &ManifestEntry{
+			Filename: account.Session.SteamID.ToString() + maFileExtension,
+			SteamID:  account.Session.SteamID,
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	pending, err := manifest.prepareAccount(entry, account, passcode)
+	if err == nil {
+		err = manifest.commitAccounts([]*pendingMaFile{pending})
+	}
+
+	if err != nil && added {
+		manifest.Entries = manifest.Entries[:len(manifest.Entries)-1]
+	}
+
+	return err
+}
+
+// ChangePasscode re-encrypts every maFile with @newPasscode, an empty
+// @newPasscode leaves them decrypted, an empty @oldPasscode means the
+// manifest is not encrypted yet.
+func (manifest *Manifest) ChangePasscode(oldPasscode, newPasscode string) error {
+	// Decrypt everything first, so a wrong passcode changes nothing.
+	accounts, err := manifest.LoadAccounts(oldPasscode)
+	if err != nil {
+		return err
+	}
+
+	encrypted := manifest.Encrypted
+	manifest.Encrypted = len(newPasscode) != 0
+
+	pending := make([]*pendingMaFile, 0, len(accounts))
+	for k, entry := range manifest.Entries {
+		p, err := manifest.prepareAccount(entry, accounts[k], newPasscode)
+		if err != nil {
+			manifest.Encrypted = encrypted
+			removePending(pending)
+			return err
+		}
+
+		pending = append(pending, p)
+	}
+
+	if err = manifest.commitAccounts(pending); err != nil {
+		manifest.Encrypted = encrypted
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package steam
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Encrypted the way SteamDesktopAuthenticator's FileEncryptor does it,
+// Rfc2898DeriveBytes(passcode, salt, 50000) then AES-256-CBC with PKCS7,
+// produced with Python's hashlib.pbkdf2_hmac and "openssl enc -aes-256-cbc".
+const (
+	sdaFixturePasscode  = "hunter2"
+	sdaFixtureSalt      = "AQIDBAUGBwg="
+	sdaFixtureIV        = "oKGio6SlpqeoqaqrrK2urw=="
+	sdaFixtureEncrypted = "n3+jkL1gJK6AKnfgl6wCGu4hXn/U4PMB1D4ykJqOYFClPU4zAQhm55kDGnih+1DdfHKMJM5xRsEsE++5Q8XXanLHnmYgEg/RmaWHubcVmGxkdjGEOtHQ8rXxQpuT9JeHwD8Nl2/72IcdsoXvmXPWUfekCNh2VGV6N6SvrRxBeaxPUAAf6WMtjd3kfQ7ukLMfabNqvpcbB2J6b4yfn/ZhOCChWI9oQfLkxgVxufANo6M="
+	sdaFixturePlain     = `{"shared_secret":"AAECAwQFBgcICQoLDA0ODxAREhM=","account_name":"testbot","device_id":"android:00000000-0000-0000-0000-000000000000","Session":{"SteamID":76561197960287930}}`
+)
+
+func TestDecryptMaFile(t *testing.T) {
+	plain, err := DecryptMaFile([]byte(sdaFixtureEncrypted), sdaFixturePasscode, sdaFixtureSalt, sdaFixtureIV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(plain) != sdaFixturePlain {
+		t.Fatalf("got %s, want %s", plain, sdaFixturePlain)
+	}
+
+	if _, err = DecryptMaFile([]byte(sdaFixtureEncrypted), "wrong", sdaFixtureSalt, sdaFixtureIV); err != ErrInvalidPasscode {
+		t.Fatalf("wrong passcode: got %v, want ErrInvalidPasscode", err)
+	}
+}
+
+func TestManifestChangePasscode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	account, err := ParseSteamGuardAccount([]byte(sdaFixturePlain))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := NewManifest(dir)
+	if err = manifest.SaveAccount(account, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = manifest.ChangePasscode("", sdaFixturePasscode); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err = LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = manifest.VerifyPasscode(sdaFixturePasscode); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := manifest.LoadAccountBySteamID(account.Session.SteamID, sdaFixturePasscode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.SharedSecret != account.SharedSecret {
+		t.Fatalf("got shared secret %s, want %s", loaded.SharedSecret, account.SharedSecret)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("got %d files, want manifest.json and one maFile", len(files))
+	}
+}
+
+func TestManifestSaveAccountRollback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	account, err := ParseSteamGuardAccount([]byte(sdaFixturePlain))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := NewManifest(dir)
+	if err = manifest.SaveAccount(account, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// A directory in the way of the second maFile makes saving it fail.
+	other, err := ParseSteamGuardAccount([]byte(sdaFixturePlain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Session.SteamID++
+
+	blocked := filepath.Join(dir, other.Session.SteamID.ToString()+maFileExtension)
+	if err = os.MkdirAll(filepath.Join(blocked, "in-the-way"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = manifest.SaveAccount(other, ""); err == nil {
+		t.Fatal("saving over a directory succeeded")
+	}
+
+	if len(manifest.Entries) != 1 {
+		t.Fatalf("got %d entries in memory, want 1", len(manifest.Entries))
+	}
+
+	if manifest, err = LoadManifest(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(manifest.Entries) != 1 {
+		t.Fatalf("got %d entries on disk, want 1", len(manifest.Entries))
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 3 {
+		t.Fatalf("got %d files, want manifest.json, one maFile and the directory", len(files))
+	}
+}
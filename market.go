@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -94,7 +95,11 @@ var (
 )
 
 func (session *Session) GetMarketItemPriceHistory(appID uint64, marketHashName string) ([]*MarketItemPrice, error) {
-	resp, err := session.client.Get("https://steamcommunity.com/market/pricehistory/?" + url.Values{
+	return session.GetMarketItemPriceHistoryContext(context.Background(), appID, marketHashName)
+}
+
+func (session *Session) GetMarketItemPriceHistoryContext(ctx context.Context, appID uint64, marketHashName string) ([]*MarketItemPrice, error) {
+	resp, err := session.get(ctx, "https://steamcommunity.com/market/pricehistory/?"+url.Values{
 		"appid":            {strconv.FormatUint(appID, 10)},
 		"market_hash_name": {marketHashName},
 	}.Encode())
@@ -106,6 +111,10 @@ func (session *Session) GetMarketItemPriceHistory(appID uint64, marketHashName s
 		return nil, err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return nil, err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
 	}
@@ -148,7 +157,11 @@ func (session *Session) GetMarketItemPriceHistory(appID uint64, marketHashName s
 }
 
 func (session *Session) GetMarketItemPriceOverview(appID uint64, country, currencyID, marketHashName string) (*MarketItemPriceOverview, error) {
-	resp, err := session.client.Get("https://steamcommunity.com/market/priceoverview/?" + url.Values{
+	return session.GetMarketItemPriceOverviewContext(context.Background(), appID, country, currencyID, marketHashName)
+}
+
+func (session *Session) GetMarketItemPriceOverviewContext(ctx context.Context, appID uint64, country, currencyID, marketHashName string) (*MarketItemPriceOverview, error) {
+	resp, err := session.get(ctx, "https://steamcommunity.com/market/priceoverview/?"+url.Values{
 		"appid":            {strconv.FormatUint(appID, 10)},
 		"country":          {country},
 		"currencyID":       {currencyID},
@@ -175,7 +188,12 @@ func (session *Session) GetMarketItemPriceOverview(appID uint64, country, curren
 }
 
 func (session *Session) SellItem(item *InventoryItem, amount, price uint64) (*MarketSellResponse, error) {
-	req, err := http.NewRequest(
+	return session.SellItemContext(context.Background(), item, amount, price)
+}
+
+func (session *Session) SellItemContext(ctx context.Context, item *InventoryItem, amount, price uint64) (*MarketSellResponse, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		"https://steamcommunity.com/market/sellitem/",
 		strings.NewReader(url.Values{
@@ -191,7 +209,7 @@ func (session *Session) SellItem(item *InventoryItem, amount, price uint64) (*Ma
 		return nil, err
 	}
 
-	profileURL, err := session.GetProfileURL()
+	profileURL, err := session.GetProfileURLContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +225,10 @@ func (session *Session) SellItem(item *InventoryItem, amount, price uint64) (*Ma
 		return nil, err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return nil, err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
 	}
@@ -220,7 +242,12 @@ func (session *Session) SellItem(item *InventoryItem, amount, price uint64) (*Ma
 }
 
 func (session *Session) PlaceBuyOrder(appid uint64, priceTotal float64, quantity uint64, currencyID, marketHashName string) (*MarketBuyOrderResponse, error) {
-	req, err := http.NewRequest(
+	return session.PlaceBuyOrderContext(context.Background(), appid, priceTotal, quantity, currencyID, marketHashName)
+}
+
+func (session *Session) PlaceBuyOrderContext(ctx context.Context, appid uint64, priceTotal float64, quantity uint64, currencyID, marketHashName string) (*MarketBuyOrderResponse, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		"https://steamcommunity.com/market/createbuyorder/",
 		strings.NewReader(url.Values{
@@ -255,6 +282,10 @@ func (session *Session) PlaceBuyOrder(appid uint64, priceTotal float64, quantity
 		return nil, err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return nil, err
+	}
+
 	response := &MarketBuyOrderResponse{}
 	if err = json.NewDecoder(resp.Body).Decode(response); err != nil {
 		return nil, err
@@ -264,7 +295,12 @@ func (session *Session) PlaceBuyOrder(appid uint64, priceTotal float64, quantity
 }
 
 func (session *Session) CancelBuyOrder(orderid uint64) error {
-	req, err := http.NewRequest(
+	return session.CancelBuyOrderContext(context.Background(), orderid)
+}
+
+func (session *Session) CancelBuyOrderContext(ctx context.Context, orderid uint64) error {
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		"https://steamcommunity.com/market/cancelbuyorder/",
 		strings.NewReader(url.Values{
@@ -288,6 +324,10 @@ func (session *Session) CancelBuyOrder(orderid uint64) error {
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("cannot cancel %d: %d", orderid, resp.StatusCode)
 	}
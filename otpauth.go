@@ -0,0 +1,183 @@
+package steam
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	otpAuthScheme      = "otpauth"
+	otpAuthSteamIssuer = "Steam"
+	// Backup apps recognize the Steam alphabet (see chars) by this encoder.
+	otpAuthSteamEncoder = "steam"
+	otpAuthSteamDigits  = 5
+	otpAuthPeriod       = 30
+)
+
+var (
+	ErrInvalidOTPAuthURI   = errors.New("invalid otpauth URI")
+	ErrInvalidSharedSecret = errors.New("invalid shared secret")
+)
+
+// OTPAuthURI is an "otpauth://totp/Steam:<account>?secret=..." URI as
+// found in TwoFactorInfo.URI and understood by authenticator apps.
+type OTPAuthURI struct {
+	AccountName string
+	Issuer      string
+	// SharedSecret is base64 as used by GenerateTwoFactorCode,
+	// the URI itself holds it as base32.
+	SharedSecret string
+	Digits       int
+	Period       int
+	// Steam tells that codes use the Steam alphabet instead of decimal digits.
+	Steam bool
+}
+
+// NewOTPAuthURI returns the Steam URI for @accountName and the base64 @sharedSecret.
+func NewOTPAuthURI(accountName, sharedSecret string) (*OTPAuthURI, error) {
+	if _, err := SharedSecretToBase32(sharedSecret); err != nil || len(sharedSecret) == 0 {
+		return nil, ErrInvalidSharedSecret
+	}
+
+	return &OTPAuthURI{
+		AccountName:  accountName,
+		Issuer:       otpAuthSteamIssuer,
+		SharedSecret: sharedSecret,
+		Digits:       otpAuthSteamDigits,
+		Period:       otpAuthPeriod,
+		Steam:        true,
+	}, nil
+}
+
+// ParseOTPAuthURI parses @uri, "otpauth://steam/..." and "encoder=steam"
+// as well as the Steam issuer mark the secret as a Steam Guard one.
+func ParseOTPAuthURI(uri string) (*OTPAuthURI, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != otpAuthScheme || (u.Host != "totp" && u.Host != otpAuthSteamEncoder) {
+		return nil, ErrInvalidOTPAuthURI
+	}
+
+	query := u.Query()
+	sharedSecret, err := SharedSecretFromBase32(query.Get("secret"))
+	if err != nil || len(sharedSecret) == 0 {
+		return nil, ErrInvalidOTPAuthURI
+	}
+
+	otp := &OTPAuthURI{
+		AccountName:  strings.TrimPrefix(u.Path, "/"),
+		Issuer:       query.Get("issuer"),
+		SharedSecret: sharedSecret,
+		Period:       otpAuthPeriod,
+	}
+
+	if k := strings.IndexByte(otp.AccountName, ':'); k != -1 {
+		if len(otp.Issuer) == 0 {
+			otp.Issuer = otp.AccountName[:k]
+		}
+
+		otp.AccountName = otp.AccountName[k+1:]
+	}
+
+	otp.Steam = u.Host == otpAuthSteamEncoder ||
+		strings.EqualFold(query.Get("encoder"), otpAuthSteamEncoder) ||
+		strings.EqualFold(otp.Issuer, otpAuthSteamIssuer)
+
+	otp.Digits = 6
+	if otp.Steam {
+		otp.Digits = otpAuthSteamDigits
+	}
+
+	if digits := query.Get("digits"); len(digits) != 0 {
+		if otp.Digits, err = strconv.Atoi(digits); err != nil {
+			return nil, ErrInvalidOTPAuthURI
+		}
+	}
+
+	if period := query.Get("period"); len(period) != 0 {
+		if otp.Period, err = strconv.Atoi(period); err != nil {
+			return nil, ErrInvalidOTPAuthURI
+		}
+	}
+
+	return otp, nil
+}
+
+// String is Encode for fmt, it is empty if SharedSecret is invalid.
+func (otp *OTPAuthURI) String() string {
+	uri, _ := otp.Encode()
+	return uri
+}
+
+// Encode returns the URI, SharedSecret has to be valid base64.
+func (otp *OTPAuthURI) Encode() (string, error) {
+	secret, err := SharedSecretToBase32(otp.SharedSecret)
+	if err != nil || len(secret) == 0 {
+		return "", ErrInvalidSharedSecret
+	}
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	if len(otp.Issuer) != 0 {
+		query.Set("issuer", otp.Issuer)
+	}
+
+	if otp.Digits != 0 {
+		query.Set("digits", strconv.Itoa(otp.Digits))
+	}
+
+	if otp.Period != 0 && otp.Period != otpAuthPeriod {
+		query.Set("period", strconv.Itoa(otp.Period))
+	}
+
+	if otp.Steam {
+		query.Set("encoder", otpAuthSteamEncoder)
+	}
+
+	label := otp.AccountName
+	if len(otp.Issuer) != 0 {
+		label = otp.Issuer + ":" + label
+	}
+
+	u := url.URL{
+		Scheme:   otpAuthScheme,
+		Host:     "totp",
+		Path:     "/" + label,
+		RawQuery: query.Encode(),
+	}
+	return u.String(), nil
+}
+
+// SharedSecretFromBase32 converts an otpauth base32 secret to the base64
+// form GenerateTwoFactorCode takes.
+func SharedSecretFromBase32(secret string) (string, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	data, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// SharedSecretToBase32 converts a base64 shared secret to unpadded base32.
+func SharedSecretToBase32(sharedSecret string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sharedSecret)
+	if err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(data), nil
+}
+
+// OTPAuthURI returns the URI backup apps can import the account from.
+func (account *SteamGuardAccount) OTPAuthURI() (*OTPAuthURI, error) {
+	return NewOTPAuthURI(account.AccountName, account.SharedSecret)
+}
@@ -0,0 +1,128 @@
+package steam
+
+import "testing"
+
+func TestSharedSecretBase32(t *testing.T) {
+	tests := []struct {
+		name   string
+		base32 string
+		base64 string
+	}{
+		{"unpadded", "AAAQEAYEAUDAOCAJBIFQYDIOB4IBCEQT", "AAECAwQFBgcICQoLDA0ODxAREhM="},
+		{"padded", "AAAQEAYEAUDAOCAJBI======", "AAECAwQFBgcICQo="},
+		{"lowercase and spaces", "aaaq eaye audA ocaj", "AAECAwQFBgcICQ=="},
+	}
+
+	for _, test := range tests {
+		got, err := SharedSecretFromBase32(test.base32)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if got != test.base64 {
+			t.Fatalf("%s: got %s, want %s", test.name, got, test.base64)
+		}
+	}
+
+	got, err := SharedSecretToBase32("AAECAwQFBgcICQo=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != "AAAQEAYEAUDAOCAJBI" {
+		t.Fatalf("got %s, want unpadded AAAQEAYEAUDAOCAJBI", got)
+	}
+
+	if _, err = SharedSecretFromBase32("not base32!"); err == nil {
+		t.Fatal("invalid base32 was accepted")
+	}
+}
+
+func TestOTPAuthURIRoundTrip(t *testing.T) {
+	const uri = "otpauth://totp/Steam:testbot?digits=5&encoder=steam&issuer=Steam&secret=AAAQEAYEAUDAOCAJBIFQYDIOB4IBCEQT"
+
+	otp, err := NewOTPAuthURI("testbot", "AAECAwQFBgcICQoLDA0ODxAREhM=")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := otp.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if encoded != uri {
+		t.Fatalf("got %s, want %s", encoded, uri)
+	}
+
+	parsed, err := ParseOTPAuthURI(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *parsed != *otp {
+		t.Fatalf("got %+v, want %+v", parsed, otp)
+	}
+}
+
+func TestParseOTPAuthURI(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+		want OTPAuthURI
+	}{
+		{
+			"no issuer",
+			"otpauth://totp/testbot?secret=AAAQEAYEAUDAOCAJ&encoder=steam",
+			OTPAuthURI{AccountName: "testbot", SharedSecret: "AAECAwQFBgcICQ==", Digits: 5, Period: 30, Steam: true},
+		},
+		{
+			"issuer from label",
+			"otpauth://totp/Steam:testbot?secret=aaaqeayeaudaocaj",
+			OTPAuthURI{AccountName: "testbot", Issuer: "Steam", SharedSecret: "AAECAwQFBgcICQ==", Digits: 5, Period: 30, Steam: true},
+		},
+		{
+			"not steam",
+			"otpauth://totp/Other:testbot?secret=AAAQEAYEAUDAOCAJ&period=60",
+			OTPAuthURI{AccountName: "testbot", Issuer: "Other", SharedSecret: "AAECAwQFBgcICQ==", Digits: 6, Period: 60},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseOTPAuthURI(test.uri)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if *got != test.want {
+			t.Fatalf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+
+	for _, uri := range []string{
+		"https://totp/Steam:testbot?secret=AAAQEAYEAUDAOCAJ",
+		"otpauth://totp/Steam:testbot",
+		"otpauth://totp/Steam:testbot?secret=AAAQEAYEAUDAOCAJ&digits=five",
+	} {
+		if _, err := ParseOTPAuthURI(uri); err != ErrInvalidOTPAuthURI {
+			t.Fatalf("%s: got %v, want ErrInvalidOTPAuthURI", uri, err)
+		}
+	}
+}
+
+func TestNewOTPAuthURIInvalidSecret(t *testing.T) {
+	for _, secret := range []string{"", "not base64!"} {
+		if _, err := NewOTPAuthURI("testbot", secret); err != ErrInvalidSharedSecret {
+			t.Fatalf("%q: got %v, want ErrInvalidSharedSecret", secret, err)
+		}
+	}
+
+	otp := &OTPAuthURI{AccountName: "testbot", SharedSecret: "not base64!"}
+	if _, err := otp.Encode(); err != ErrInvalidSharedSecret {
+		t.Fatalf("got %v, want ErrInvalidSharedSecret", err)
+	}
+
+	if otp.String() != "" {
+		t.Fatalf("got %s, want an empty string", otp.String())
+	}
+}
@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -82,6 +83,10 @@ type Friend struct {
 }
 
 func (session *Session) GetProfileURL() (string, error) {
+	return session.GetProfileURLContext(context.Background())
+}
+
+func (session *Session) GetProfileURLContext(ctx context.Context) (string, error) {
 	tmpClient := http.Client{Jar: session.client.Jar}
 
 	/* We do not follow redirect, we want to know where it'd redirect us.  */
@@ -90,7 +95,12 @@ func (session *Session) GetProfileURL() (string, error) {
 	}
 
 	/* Query normal, this will redirect us.  */
-	resp, err := tmpClient.Get("https://steamcommunity.com/my")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://steamcommunity.com/my", nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := tmpClient.Do(req)
 	if resp == nil {
 		return "", err
 	}
@@ -101,11 +111,43 @@ func (session *Session) GetProfileURL() (string, error) {
 	}
 
 	/* We now have a few useful variables in header, for now, we will just grap "Location".  */
-	return resp.Header.Get("Location"), nil
+	location, err := resp.Location()
+	if err != nil {
+		return "", err
+	}
+
+	/* Logged out sessions are sent to the login page instead of the profile.  */
+	if isLoginURL(location) {
+		return "", ErrNotLoggedIn
+	}
+
+	return location.String(), nil
+}
+
+// IsLoggedIn probes whether the web cookies of the session are still valid.
+func (session *Session) IsLoggedIn() (bool, error) {
+	return session.IsLoggedInContext(context.Background())
+}
+
+func (session *Session) IsLoggedInContext(ctx context.Context) (bool, error) {
+	_, err := session.GetProfileURLContext(ctx)
+	if err == ErrNotLoggedIn {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 func (session *Session) SetupProfile(profileURL string) error {
-	resp, err := session.client.Get(profileURL + "/edit?welcomed=1")
+	return session.SetupProfileContext(context.Background(), profileURL)
+}
+
+func (session *Session) SetupProfileContext(ctx context.Context, profileURL string) error {
+	resp, err := session.get(ctx, profileURL+"/edit?welcomed=1")
 	if resp != nil {
 		resp.Body.Close()
 	}
@@ -114,6 +156,10 @@ func (session *Session) SetupProfile(profileURL string) error {
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("http error: %d", resp.StatusCode)
 	}
@@ -122,10 +168,14 @@ func (session *Session) SetupProfile(profileURL string) error {
 }
 
 func (session *Session) SetProfileInfo(profileURL string, values *map[string][]string) error {
+	return session.SetProfileInfoContext(context.Background(), profileURL, values)
+}
+
+func (session *Session) SetProfileInfoContext(ctx context.Context, profileURL string, values *map[string][]string) error {
 	(*values)["sessionID"] = []string{session.sessionID}
 	(*values)["type"] = []string{"profileSave"}
 
-	resp, err := session.client.PostForm(profileURL+"/edit", *values)
+	resp, err := session.postForm(ctx, profileURL+"/edit", *values)
 	if resp != nil {
 		resp.Body.Close()
 	}
@@ -134,6 +184,10 @@ func (session *Session) SetProfileInfo(profileURL string, values *map[string][]s
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("http error: %d", resp.StatusCode)
 	}
@@ -142,7 +196,11 @@ func (session *Session) SetProfileInfo(profileURL string, values *map[string][]s
 }
 
 func (session *Session) SetProfilePrivacy(profileURL string, commentPrivacy string, privacy uint8) error {
-	resp, err := session.client.PostForm(profileURL+"/edit/settings", url.Values{
+	return session.SetProfilePrivacyContext(context.Background(), profileURL, commentPrivacy, privacy)
+}
+
+func (session *Session) SetProfilePrivacyContext(ctx context.Context, profileURL string, commentPrivacy string, privacy uint8) error {
+	resp, err := session.postForm(ctx, profileURL+"/edit/settings", url.Values{
 		"sessionID":               {session.sessionID},
 		"type":                    {"profileSettings"},
 		"commentSetting":          {commentPrivacy},
@@ -158,6 +216,10 @@ func (session *Session) SetProfilePrivacy(profileURL string, commentPrivacy stri
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("http error: %d", resp.StatusCode)
 	}
@@ -166,7 +228,11 @@ func (session *Session) SetProfilePrivacy(profileURL string, commentPrivacy stri
 }
 
 func (session *Session) GetPlayerSummaries(steamids string) ([]*PlayerSummary, error) {
-	resp, err := session.client.Get(apiGetPlayerSummaries + url.Values{
+	return session.GetPlayerSummariesContext(context.Background(), steamids)
+}
+
+func (session *Session) GetPlayerSummariesContext(ctx context.Context, steamids string) ([]*PlayerSummary, error) {
+	resp, err := session.get(ctx, apiGetPlayerSummaries+url.Values{
 		"key":      {session.apiKey},
 		"steamids": {steamids},
 	}.Encode())
@@ -195,7 +261,11 @@ func (session *Session) GetPlayerSummaries(steamids string) ([]*PlayerSummary, e
 }
 
 func (session *Session) GetOwnedGames(sid SteamID, freeGames bool, appInfo bool) (*OwnedGamesResponse, error) {
-	resp, err := session.client.Get(apiGetOwnedGames + url.Values{
+	return session.GetOwnedGamesContext(context.Background(), sid, freeGames, appInfo)
+}
+
+func (session *Session) GetOwnedGamesContext(ctx context.Context, sid SteamID, freeGames bool, appInfo bool) (*OwnedGamesResponse, error) {
+	resp, err := session.get(ctx, apiGetOwnedGames+url.Values{
 		"key":                       {session.apiKey},
 		"steamid":                   {sid.ToString()},
 		"format":                    {"json"},
@@ -223,7 +293,11 @@ func (session *Session) GetOwnedGames(sid SteamID, freeGames bool, appInfo bool)
 }
 
 func (session *Session) GetPlayerBans(steamids string) ([]*PlayerBan, error) {
-	resp, err := session.client.Get(apiGetPlayerBans + url.Values{
+	return session.GetPlayerBansContext(context.Background(), steamids)
+}
+
+func (session *Session) GetPlayerBansContext(ctx context.Context, steamids string) ([]*PlayerBan, error) {
+	resp, err := session.get(ctx, apiGetPlayerBans+url.Values{
 		"key":      {session.apiKey},
 		"steamids": {steamids},
 	}.Encode())
@@ -248,7 +322,11 @@ func (session *Session) GetPlayerBans(steamids string) ([]*PlayerBan, error) {
 }
 
 func (session *Session) GetFriends(sid SteamID) ([]*Friend, error) {
-	resp, err := session.client.Get(apiGetPlayerFriends + url.Values{
+	return session.GetFriendsContext(context.Background(), sid)
+}
+
+func (session *Session) GetFriendsContext(ctx context.Context, sid SteamID) ([]*Friend, error) {
+	resp, err := session.get(ctx, apiGetPlayerFriends+url.Values{
 		"key":     {session.apiKey},
 		"steamid": {sid.ToString()},
 		"format":  {"json"},
@@ -278,7 +356,11 @@ func (session *Session) GetFriends(sid SteamID) ([]*Friend, error) {
 }
 
 func (session *Session) ResolveVanityURL(vanityURL string) (uint64, error) {
-	resp, err := session.client.Get(apiResolveVanityURL + url.Values{
+	return session.ResolveVanityURLContext(context.Background(), vanityURL)
+}
+
+func (session *Session) ResolveVanityURLContext(ctx context.Context, vanityURL string) (uint64, error) {
+	resp, err := session.get(ctx, apiResolveVanityURL+url.Values{
 		"key":       {session.apiKey},
 		"vanityurl": {vanityURL},
 	}.Encode())
@@ -0,0 +1,137 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// CredentialProvider supplies what ReAuthenticator needs to log in again,
+// it is asked every time so secrets can be rotated in between.
+type CredentialProvider interface {
+	Credentials() (accountName, password, sharedSecret string, err error)
+}
+
+// StaticCredentials is a CredentialProvider with fixed values.
+type StaticCredentials struct {
+	AccountName  string
+	Password     string
+	SharedSecret string
+}
+
+func (credentials *StaticCredentials) Credentials() (string, string, string, error) {
+	return credentials.AccountName, credentials.Password, credentials.SharedSecret, nil
+}
+
+// ReAuthenticator is an opt-in wrapper around a Session, calls made through
+// Do that fail with ErrNotLoggedIn renew the session and are replayed once.
+// Calls run concurrently with each other but never with a renewal, which
+// rewrites the session, so the session must only be used through Do.
+// Nothing in Session uses it on its own.
+type ReAuthenticator struct {
+	session     *Session
+	credentials CredentialProvider
+
+	// Read locked by calls, write locked by renewals.
+	mu         sync.RWMutex
+	generation uint64
+}
+
+func NewReAuthenticator(session *Session, credentials CredentialProvider) *ReAuthenticator {
+	return &ReAuthenticator{
+		session:     session,
+		credentials: credentials,
+	}
+}
+
+// Session returns the wrapped session, using it outside Do races with renewals.
+func (reauth *ReAuthenticator) Session() *Session {
+	return reauth.session
+}
+
+// Do runs @call, if it fails because the session expired, the session is
+// renewed and @call is run a second time, its error is returned as is.
+func (reauth *ReAuthenticator) Do(call func(session *Session) error) error {
+	return reauth.DoContext(context.Background(), call)
+}
+
+// DoContext is Do with @ctx used while renewing the session, @call is
+// expected to pass its own context to the Session methods it uses.
+func (reauth *ReAuthenticator) DoContext(ctx context.Context, call func(session *Session) error) error {
+	generation, err := reauth.call(call)
+	if !errors.Is(err, ErrNotLoggedIn) {
+		return err
+	}
+
+	if err = reauth.renew(ctx, generation); err != nil {
+		return err
+	}
+
+	_, err = reauth.call(call)
+	return err
+}
+
+// call runs @call under the read lock and tells which session generation it used.
+func (reauth *ReAuthenticator) call(call func(session *Session) error) (uint64, error) {
+	reauth.mu.RLock()
+	defer reauth.mu.RUnlock()
+
+	return reauth.generation, call(reauth.session)
+}
+
+// renew logs in again unless another call already did it since @generation,
+// it waits for the calls in progress to return first.
+func (reauth *ReAuthenticator) renew(ctx context.Context, generation uint64) error {
+	reauth.mu.Lock()
+	defer reauth.mu.Unlock()
+
+	if reauth.generation != generation {
+		return nil
+	}
+
+	if err := reauth.login(ctx); err != nil {
+		return err
+	}
+
+	reauth.generation++
+	return nil
+}
+
+func (reauth *ReAuthenticator) login(ctx context.Context) error {
+	// A refresh token is cheaper than a full login and needs no two factor code.
+	if len(reauth.session.refreshToken) != 0 {
+		if err := reauth.session.RefreshSessionContext(ctx); err == nil {
+			if loggedIn, err := reauth.session.IsLoggedInContext(ctx); err == nil && loggedIn {
+				return nil
+			}
+		}
+	}
+
+	accountName, password, sharedSecret, err := reauth.credentials.Credentials()
+	if err != nil {
+		return err
+	}
+
+	// Without a TimeAligner on the session, align this single login by hand.
+	var timeOffset time.Duration
+	if _, aligned := reauth.session.Clock().(*TimeAligner); !aligned && len(sharedSecret) != 0 {
+		timeTip, err := GetTimeTipContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		timeOffset = time.Duration(timeTip.Time-time.Now().Unix()) * time.Second
+	}
+
+	// A full login derives a device ID of its own, confirmations need the one
+	// of the authenticator, as set by SteamGuardAccount.Login.
+	deviceID := reauth.session.deviceID
+	defer func() {
+		if len(deviceID) != 0 {
+			reauth.session.deviceID = deviceID
+		}
+	}()
+
+	return reauth.session.LoginContext(ctx, accountName, password, sharedSecret, timeOffset)
+}
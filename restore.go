@@ -16,17 +16,18 @@ type CookieData struct {
 }
 
 type SessionData struct {
-	SteamID     uint64     `json:"steam_id"`
-	SessionID   string     `json:"session_id"`
-	DeviceID    string     `json:"device_id"`
-	UmqID       string     `json:"umq_id"`
-	Token       string     `json:"token"`
-	LoginSecure string     `json:"login_secure"`
-	WebCookie   string     `json:"webcookie"`
-	ApiKey      string     `json:"api_key"`
-	ChatMessage int        `json:"chat_message"`
-	Language    string     `json:"language"`
-	Cookies     CookieData `json:"cookies"`
+	SteamID      uint64     `json:"steam_id"`
+	SessionID    string     `json:"session_id"`
+	DeviceID     string     `json:"device_id"`
+	UmqID        string     `json:"umq_id"`
+	Token        string     `json:"token"`
+	RefreshToken string     `json:"refresh_token"`
+	LoginSecure  string     `json:"login_secure"`
+	WebCookie    string     `json:"webcookie"`
+	ApiKey       string     `json:"api_key"`
+	ChatMessage  int        `json:"chat_message"`
+	Language     string     `json:"language"`
+	Cookies      CookieData `json:"cookies"`
 }
 
 var (
@@ -60,10 +61,11 @@ func (session *Session) Dump() (*SessionData, error) {
 		Cookies: cookieData,
 
 		//OAuth
-		SteamID:     uint64(session.oauth.SteamID),
-		Token:       session.oauth.Token,
-		LoginSecure: session.oauth.LoginSecure,
-		WebCookie:   session.oauth.WebCookie,
+		SteamID:      uint64(session.oauth.SteamID),
+		Token:        session.oauth.Token,
+		RefreshToken: session.refreshToken,
+		LoginSecure:  session.oauth.LoginSecure,
+		WebCookie:    session.oauth.WebCookie,
 
 		//Session
 		SessionID:   session.sessionID,
@@ -115,15 +117,16 @@ func RestoreSession(client *http.Client, data *SessionData, debug bool) (*Sessio
 	}
 
 	session := Session{
-		client:      client,
-		oauth:       oauth,
-		sessionID:   data.SessionID,
-		apiKey:      data.ApiKey,
-		deviceID:    data.DeviceID,
-		umqID:       data.UmqID,
-		chatMessage: data.ChatMessage,
-		language:    data.Language,
-		debug:       debug,
+		client:       client,
+		oauth:        oauth,
+		refreshToken: data.RefreshToken,
+		sessionID:    data.SessionID,
+		apiKey:       data.ApiKey,
+		deviceID:     data.DeviceID,
+		umqID:        data.UmqID,
+		chatMessage:  data.ChatMessage,
+		language:     data.Language,
+		debug:        debug,
 	}
 
 	return &session, nil
@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"encoding/json"
 	"net/url"
 	"strconv"
@@ -11,7 +12,11 @@ const (
 )
 
 func (session *Session) GetRequiredSteamAppVersion(appID int) (int, error) {
-	resp, err := session.client.Get(apiUpToDateCheck + url.Values{
+	return session.GetRequiredSteamAppVersionContext(context.Background(), appID)
+}
+
+func (session *Session) GetRequiredSteamAppVersionContext(ctx context.Context, appID int) (int, error) {
+	resp, err := session.get(ctx, apiUpToDateCheck+url.Values{
 		"appid":   {strconv.Itoa(appID)},
 		"version": {"0"},
 	}.Encode())
@@ -0,0 +1,182 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+var ErrNoSteamGuardSession = errors.New("steam guard account has no session")
+
+// SteamGuardSession is the "Session" block of a maFile, older
+// SteamDesktopAuthenticator versions store OAuthToken and SteamLogin,
+// newer ones AccessToken and RefreshToken.
+type SteamGuardSession struct {
+	SteamID          SteamID `json:"SteamID"`
+	SessionID        string  `json:"SessionID,omitempty"`
+	SteamLogin       string  `json:"SteamLogin,omitempty"`
+	SteamLoginSecure string  `json:"SteamLoginSecure,omitempty"`
+	WebCookie        string  `json:"WebCookie,omitempty"`
+	OAuthToken       string  `json:"OAuthToken,omitempty"`
+	AccessToken      string  `json:"AccessToken,omitempty"`
+	RefreshToken     string  `json:"RefreshToken,omitempty"`
+}
+
+// SteamGuardAccount holds the secrets of a mobile authenticator, it is
+// stored as SteamDesktopAuthenticator ".maFile" JSON.
+type SteamGuardAccount struct {
+	SharedSecret   string             `json:"shared_secret"`
+	SerialNumber   string             `json:"serial_number"`
+	RevocationCode string             `json:"revocation_code"`
+	URI            string             `json:"uri"`
+	ServerTime     int64              `json:"server_time"`
+	AccountName    string             `json:"account_name"`
+	TokenGID       string             `json:"token_gid"`
+	IdentitySecret string             `json:"identity_secret"`
+	Secret1        string             `json:"secret_1"`
+	Status         int32              `json:"status"`
+	DeviceID       string             `json:"device_id"`
+	FullyEnrolled  bool               `json:"fully_enrolled"`
+	Session        *SteamGuardSession `json:"Session,omitempty"`
+}
+
+// NewSteamGuardAccount wraps @info as returned by EnableTwoFactor, the
+// @deviceID has to be the one the authenticator was added with.
+func NewSteamGuardAccount(accountName, deviceID string, info *TwoFactorInfo) *SteamGuardAccount {
+	return &SteamGuardAccount{
+		SharedSecret:   info.SharedSecret,
+		SerialNumber:   strconv.FormatUint(info.SerialNumber, 10),
+		RevocationCode: info.RevocationCode,
+		URI:            info.URI,
+		ServerTime:     int64(info.ServerTime),
+		AccountName:    accountName,
+		TokenGID:       info.TokenGID,
+		IdentitySecret: info.IdentitySecret,
+		Secret1:        info.Secret1,
+		Status:         int32(info.Status),
+		DeviceID:       deviceID,
+	}
+}
+
+func ParseSteamGuardAccount(data []byte) (*SteamGuardAccount, error) {
+	account := &SteamGuardAccount{}
+	if err := json.Unmarshal(data, account); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func LoadSteamGuardAccount(path string) (*SteamGuardAccount, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSteamGuardAccount(data)
+}
+
+func (account *SteamGuardAccount) Marshal() ([]byte, error) {
+	return json.Marshal(account)
+}
+
+// Save writes the account to @path readable by the owner only.
+func (account *SteamGuardAccount) Save(path string) error {
+	data, err := account.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+func (account *SteamGuardAccount) TwoFactorInfo() *TwoFactorInfo {
+	serialNumber, _ := strconv.ParseUint(account.SerialNumber, 10, 64)
+	return &TwoFactorInfo{
+		Status:         uint32(account.Status),
+		SharedSecret:   account.SharedSecret,
+		IdentitySecret: account.IdentitySecret,
+		Secret1:        account.Secret1,
+		SerialNumber:   serialNumber,
+		RevocationCode: account.RevocationCode,
+		URI:            account.URI,
+		ServerTime:     uint64(account.ServerTime),
+		TokenGID:       account.TokenGID,
+		AccountName:    account.AccountName,
+	}
+}
+
+func (account *SteamGuardAccount) GenerateTwoFactorCode(current int64) (string, error) {
+	return GenerateTwoFactorCode(account.SharedSecret, current)
+}
+
+func (account *SteamGuardAccount) VerifyTwoFactorCode(code string, current int64, window int) (int, bool, error) {
+	return VerifyTwoFactorCode(account.SharedSecret, code, current, window)
+}
+
+func (account *SteamGuardAccount) GenerateConfirmationCode(tag string, current int64) (string, error) {
+	return GenerateConfirmationCode(account.IdentitySecret, tag, current)
+}
+
+// SetSession stores the tokens of @session in the account "Session" block.
+func (account *SteamGuardAccount) SetSession(session *Session) {
+	account.Session = &SteamGuardSession{
+		SteamID:          session.oauth.SteamID,
+		SessionID:        session.sessionID,
+		SteamLoginSecure: session.oauth.LoginSecure,
+		WebCookie:        session.oauth.WebCookie,
+		AccessToken:      session.oauth.Token,
+		RefreshToken:     session.refreshToken,
+	}
+}
+
+// Login logs @session in with the shared secret of the account and makes it
+// use the authenticator device ID, as confirmations require.
+func (account *SteamGuardAccount) Login(session *Session, password string) error {
+	return account.LoginContext(context.Background(), session, password)
+}
+
+func (account *SteamGuardAccount) LoginContext(ctx context.Context, session *Session, password string) error {
+	if err := session.LoginContext(ctx, account.AccountName, password, account.SharedSecret, 0); err != nil {
+		return err
+	}
+
+	if len(account.DeviceID) != 0 {
+		session.deviceID = account.DeviceID
+	}
+
+	account.SetSession(session)
+	return nil
+}
+
+// NewSession restores a Session from the account "Session" block.
+func (account *SteamGuardAccount) NewSession(client *http.Client, debug bool) (*Session, error) {
+	if account.Session == nil {
+		return nil, ErrNoSteamGuardSession
+	}
+
+	token := account.Session.AccessToken
+	if len(token) == 0 {
+		token = account.Session.OAuthToken
+	}
+
+	loginSecure := account.Session.SteamLoginSecure
+	if len(loginSecure) == 0 && len(account.Session.AccessToken) != 0 {
+		loginSecure = url.QueryEscape(account.Session.SteamID.ToString() + "||" + account.Session.AccessToken)
+	}
+
+	return RestoreSession(client, &SessionData{
+		SteamID:      uint64(account.Session.SteamID),
+		SessionID:    account.Session.SessionID,
+		DeviceID:     account.DeviceID,
+		Token:        token,
+		RefreshToken: account.Session.RefreshToken,
+		LoginSecure:  loginSecure,
+		WebCookie:    account.Session.WebCookie,
+		Language:     "english",
+	}, debug)
+}
@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,7 +25,11 @@ func (session *Session) PrepareForSteamStore() {
 }
 
 func (session *Session) ValidatePhoneNumber(number string) error {
-	resp, err := session.client.Get("https://store.steampowered.com/phone/validate?phoneNumber=" + url.QueryEscape(number))
+	return session.ValidatePhoneNumberContext(context.Background(), number)
+}
+
+func (session *Session) ValidatePhoneNumberContext(ctx context.Context, number string) error {
+	resp, err := session.get(ctx, "https://store.steampowered.com/phone/validate?phoneNumber="+url.QueryEscape(number))
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -33,6 +38,10 @@ func (session *Session) ValidatePhoneNumber(number string) error {
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	var response PhoneAPIResponse
 	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return err
@@ -46,7 +55,11 @@ func (session *Session) ValidatePhoneNumber(number string) error {
 }
 
 func (session *Session) AddPhoneNumber(number string) error {
-	resp, err := session.client.Get("https://store.steampowered.com/phone/add_ajaxop?" + url.Values{
+	return session.AddPhoneNumberContext(context.Background(), number)
+}
+
+func (session *Session) AddPhoneNumberContext(ctx context.Context, number string) error {
+	resp, err := session.get(ctx, "https://store.steampowered.com/phone/add_ajaxop?"+url.Values{
 		"op":        {"get_phone_number"},
 		"input":     {number},
 		"sessionID": {session.sessionID},
@@ -60,6 +73,10 @@ func (session *Session) AddPhoneNumber(number string) error {
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	var response PhoneAPIResponse
 	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return err
@@ -73,7 +90,11 @@ func (session *Session) AddPhoneNumber(number string) error {
 }
 
 func (session *Session) InitiateRemovePhoneNumber() error {
-	resp, err := session.client.PostForm("https://store.steampowered.com/phone/remove_confirm_sms", url.Values{
+	return session.InitiateRemovePhoneNumberContext(context.Background())
+}
+
+func (session *Session) InitiateRemovePhoneNumberContext(ctx context.Context) error {
+	resp, err := session.postForm(ctx, "https://store.steampowered.com/phone/remove_confirm_sms", url.Values{
 		"sessionID": {session.sessionID},
 		"bWasEdit":  {""},
 	})
@@ -85,6 +106,10 @@ func (session *Session) InitiateRemovePhoneNumber() error {
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("http error: %d", resp.StatusCode)
 	}
@@ -93,7 +118,11 @@ func (session *Session) InitiateRemovePhoneNumber() error {
 }
 
 func (session *Session) ConfirmRemovePhoneNumber(mobileCode string) error {
-	resp, err := session.client.PostForm("https://store.steampowered.com/phone/remove_confirm_smscode_entry", url.Values{
+	return session.ConfirmRemovePhoneNumberContext(context.Background(), mobileCode)
+}
+
+func (session *Session) ConfirmRemovePhoneNumberContext(ctx context.Context, mobileCode string) error {
+	resp, err := session.postForm(ctx, "https://store.steampowered.com/phone/remove_confirm_smscode_entry", url.Values{
 		"sessionID": {session.sessionID},
 		"bWasEdit":  {""},
 		"smscode":   {mobileCode},
@@ -107,6 +136,10 @@ func (session *Session) ConfirmRemovePhoneNumber(mobileCode string) error {
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("http error: %d", resp.StatusCode)
 	}
@@ -115,7 +148,11 @@ func (session *Session) ConfirmRemovePhoneNumber(mobileCode string) error {
 }
 
 func (session *Session) ReSendVerificationCode() error {
-	resp, err := session.client.Get("https://store.steampowered.com/phone/add_ajaxop?" + url.Values{
+	return session.ReSendVerificationCodeContext(context.Background())
+}
+
+func (session *Session) ReSendVerificationCodeContext(ctx context.Context) error {
+	resp, err := session.get(ctx, "https://store.steampowered.com/phone/add_ajaxop?"+url.Values{
 		"op":        {"resend_sms"},
 		"input":     {""},
 		"sessionID": {session.sessionID},
@@ -129,6 +166,10 @@ func (session *Session) ReSendVerificationCode() error {
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	var response PhoneAPIResponse
 	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return err
@@ -146,7 +187,11 @@ func (session *Session) ReSendVerificationCode() error {
 }
 
 func (session *Session) VerifyPhoneNumber(code string) error {
-	resp, err := session.client.Get("https://store.steampowered.com/phone/add_ajaxop?" + url.Values{
+	return session.VerifyPhoneNumberContext(context.Background(), code)
+}
+
+func (session *Session) VerifyPhoneNumberContext(ctx context.Context, code string) error {
+	resp, err := session.get(ctx, "https://store.steampowered.com/phone/add_ajaxop?"+url.Values{
 		"op":        {"get_sms_code"},
 		"input":     {code},
 		"sessionID": {session.sessionID},
@@ -160,6 +205,10 @@ func (session *Session) VerifyPhoneNumber(code string) error {
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	var response PhoneAPIResponse
 	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return err
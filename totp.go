@@ -1,12 +1,16 @@
 package steam
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 )
 
 const (
@@ -14,6 +18,8 @@ const (
 	charsLen = uint32(len(chars))
 )
 
+var ErrEmptyTimeTipResponse = errors.New("empty time tip response")
+
 type ServerTimeTip struct {
 	Time                              int64  `json:"server_time,string"`
 	SkewToleranceSeconds              uint32 `json:"skew_tolerance_seconds,string"`
@@ -53,6 +59,31 @@ func GenerateTwoFactorCode(sharedSecret string, current int64) (string, error) {
 	return string(buf), nil
 }
 
+// VerifyTwoFactorCode checks @code against the codes of the 30 second
+// steps within @window steps before and after @current, @step is the
+// offset of the step that matched, 0 being the current one.
+func VerifyTwoFactorCode(sharedSecret, code string, current int64, window int) (step int, ok bool, err error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for k := 0; k <= window; k++ {
+		for _, step = range []int{k, -k} {
+			expected, err := GenerateTwoFactorCode(sharedSecret, current+int64(step)*30)
+			if err != nil {
+				return 0, false, err
+			}
+
+			if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+				return step, true, nil
+			}
+
+			if k == 0 {
+				break
+			}
+		}
+	}
+
+	return 0, false, nil
+}
+
 func GenerateConfirmationCode(identitySecret, tag string, current int64) (string, error) {
 	data, err := base64.StdEncoding.DecodeString(identitySecret)
 	if err != nil {
@@ -73,7 +104,17 @@ func GenerateConfirmationCode(identitySecret, tag string, current int64) (string
 }
 
 func GetTimeTip() (*ServerTimeTip, error) {
-	resp, err := http.Post("https://api.steampowered.com/ITwoFactorService/QueryTime/v1/", "application/x-www-form-urlencoded", nil)
+	return GetTimeTipContext(context.Background())
+}
+
+func GetTimeTipContext(ctx context.Context) (*ServerTimeTip, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.steampowered.com/ITwoFactorService/QueryTime/v1/", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -91,5 +132,9 @@ func GetTimeTip() (*ServerTimeTip, error) {
 		return nil, err
 	}
 
+	if response.Inner == nil {
+		return nil, ErrEmptyTimeTipResponse
+	}
+
 	return response.Inner, nil
 }
@@ -0,0 +1,62 @@
+package steam
+
+import "testing"
+
+// The codes below were computed with Python's hmac, apart from this package.
+const (
+	totpFixtureSecret = "AAECAwQFBgcICQoLDA0ODxAREhM="
+	totpFixtureTime   = 1700000000
+)
+
+func TestGenerateTwoFactorCode(t *testing.T) {
+	tests := []struct {
+		current int64
+		code    string
+	}{
+		{totpFixtureTime - 60, "5FHRB"},
+		{totpFixtureTime - 30, "BF6VH"},
+		{totpFixtureTime, "7MQGM"},
+		{totpFixtureTime + 30, "MQV58"},
+		{totpFixtureTime + 60, "25J7P"},
+	}
+
+	for _, test := range tests {
+		code, err := GenerateTwoFactorCode(totpFixtureSecret, test.current)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if code != test.code {
+			t.Fatalf("%d: got %s, want %s", test.current, code, test.code)
+		}
+	}
+}
+
+func TestVerifyTwoFactorCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   string
+		window int
+		step   int
+		ok     bool
+	}{
+		{"current, no window", "7MQGM", 0, 0, true},
+		{"previous, no window", "BF6VH", 0, 0, false},
+		{"two steps back, window 2", "5FHRB", 2, -2, true},
+		{"two steps back, window 1", "5FHRB", 1, 0, false},
+		{"one step ahead, window 1", "MQV58", 1, 1, true},
+		{"lowercase and spaces", " mqv58 ", 1, 1, true},
+		{"wrong code", "22222", 2, 0, false},
+	}
+
+	for _, test := range tests {
+		step, ok, err := VerifyTwoFactorCode(totpFixtureSecret, test.code, totpFixtureTime, test.window)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if ok != test.ok || step != test.step {
+			t.Fatalf("%s: got step %d ok %v, want step %d ok %v", test.name, step, ok, test.step, test.ok)
+		}
+	}
+}
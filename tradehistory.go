@@ -0,0 +1,253 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// Trade.Status values
+const (
+	TradeStatusInit = iota
+	TradeStatusPreCommitted
+	TradeStatusCommitted
+	TradeStatusComplete
+	TradeStatusFailed
+	TradeStatusPartialSupportRollback
+	TradeStatusFullSupportRollback
+	TradeStatusSupportRollbackSelective
+	TradeStatusRollbackFailed
+	TradeStatusRollbackAbandoned
+	TradeStatusInEscrow
+	TradeStatusEscrowRollback
+)
+
+const apiGetTradeHistory = "https://api.steampowered.com/IEconService/GetTradeHistory/v1/?"
+
+var ErrEmptyTradeHistoryResponse = errors.New("empty trade history response")
+
+// TradeAsset is an item that changed hands in a trade, NewAssetID and
+// NewContextID are its IDs in the inventory it moved to.
+type TradeAsset struct {
+	AppID        uint32        `json:"appid"`
+	ContextID    uint64        `json:"contextid,string"`
+	AssetID      uint64        `json:"assetid,string"`
+	ClassID      uint64        `json:"classid,string"`
+	InstanceID   uint64        `json:"instanceid,string"`
+	Amount       uint64        `json:"amount,string"`
+	NewAssetID   uint64        `json:"new_assetid,string"`
+	NewContextID uint64        `json:"new_contextid,string"`
+	Desc         *EconItemDesc `json:"-"` /* May be nil  */
+}
+
+type Trade struct {
+	ID             uint64        `json:"tradeid,string"`
+	Partner        SteamID       `json:"steamid_other,string"`
+	Created        int64         `json:"time_init"`
+	Updated        int64         `json:"time_mod"`
+	EscrowEndDate  int64         `json:"time_escrow_end"`
+	Status         uint32        `json:"status"`
+	AssetsGiven    []*TradeAsset `json:"assets_given"`
+	AssetsReceived []*TradeAsset `json:"assets_received"`
+}
+
+// TradeHistoryOptions selects a page of trade history, trades come newest
+// first and StartAfterTime/StartAfterTradeID are the cursor, the last trade
+// of the previous page, unless NavigatingBack is set.
+type TradeHistoryOptions struct {
+	MaxTrades         uint32
+	StartAfterTime    int64
+	StartAfterTradeID uint64
+	NavigatingBack    bool
+	IncludeFailed     bool
+	Descriptions      bool
+}
+
+type tradeHistoryResponse struct {
+	Trades       []*Trade        `json:"trades"`
+	More         bool            `json:"more"`
+	Total        uint32          `json:"total_trades"`
+	Descriptions []*EconItemDesc `json:"descriptions"`
+}
+
+// attachDescriptions sets Desc of every asset in the trades of @response,
+// a page of history can mix apps so assets are matched by app too.
+func (response *tradeHistoryResponse) attachDescriptions() {
+	if len(response.Descriptions) == 0 {
+		return
+	}
+
+	descriptions := newEconDescriptions(response.Descriptions)
+	for _, trade := range response.Trades {
+		for _, assets := range [][]*TradeAsset{trade.AssetsGiven, trade.AssetsReceived} {
+			for _, asset := range assets {
+				asset.Desc = descriptions.find(asset.AppID, asset.ClassID, asset.InstanceID)
+			}
+		}
+	}
+}
+
+type TradeHistoryPage struct {
+	Trades []*Trade
+	More   bool
+	Total  uint32
+}
+
+func (session *Session) GetTradeHistory(options *TradeHistoryOptions) (*TradeHistoryPage, error) {
+	return session.GetTradeHistoryContext(context.Background(), options)
+}
+
+// GetTradeHistoryContext fetches a page of trade history, nil @options
+// means the newest trades with the default page size.
+func (session *Session) GetTradeHistoryContext(ctx context.Context, options *TradeHistoryOptions) (*TradeHistoryPage, error) {
+	if options == nil {
+		options = &TradeHistoryOptions{}
+	}
+
+	params := url.Values{
+		"key":           {session.apiKey},
+		"include_total": {"1"},
+	}
+
+	if options.MaxTrades != 0 {
+		params.Set("max_trades", strconv.FormatUint(uint64(options.MaxTrades), 10))
+	}
+
+	if options.StartAfterTime != 0 {
+		params.Set("start_after_time", strconv.FormatInt(options.StartAfterTime, 10))
+	}
+
+	if options.StartAfterTradeID != 0 {
+		params.Set("start_after_tradeid", strconv.FormatUint(options.StartAfterTradeID, 10))
+	}
+
+	if options.NavigatingBack {
+		params.Set("navigating_back", "1")
+	}
+
+	if options.IncludeFailed {
+		params.Set("include_failed", "1")
+	}
+
+	if options.Descriptions {
+		params.Set("get_descriptions", "1")
+		params.Set("language", session.language)
+	}
+
+	resp, err := session.get(ctx, apiGetTradeHistory+params.Encode())
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkLoggedIn(resp); err != nil {
+		return nil, err
+	}
+
+	type Response struct {
+		Inner *tradeHistoryResponse `json:"response"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrEmptyTradeHistoryResponse
+	}
+
+	response.Inner.attachDescriptions()
+	return &TradeHistoryPage{
+		Trades: response.Inner.Trades,
+		More:   response.Inner.More,
+		Total:  response.Inner.Total,
+	}, nil
+}
+
+// TradeHistoryIterator goes through the whole trade history a page at a time:
+//
+//	it := session.NewTradeHistoryIterator(&steam.TradeHistoryOptions{MaxTrades: 100})
+//	for it.Next() {
+//		trade := it.Trade()
+//	}
+//	err := it.Err()
+type TradeHistoryIterator struct {
+	session *Session
+	options TradeHistoryOptions
+	trades  []*Trade
+	trade   *Trade
+	more    bool
+	err     error
+}
+
+// NewTradeHistoryIterator walks from the cursor of @options to the oldest
+// trade, NavigatingBack is ignored and a zero MaxTrades means pages of 100.
+func (session *Session) NewTradeHistoryIterator(options *TradeHistoryOptions) *TradeHistoryIterator {
+	it := &TradeHistoryIterator{
+		session: session,
+		more:    true,
+	}
+	if options != nil {
+		it.options = *options
+	}
+	it.options.NavigatingBack = false
+
+	if it.options.MaxTrades == 0 {
+		it.options.MaxTrades = 100
+	}
+
+	return it
+}
+
+func (it *TradeHistoryIterator) Next() bool {
+	return it.NextContext(context.Background())
+}
+
+// NextContext moves to the next trade, fetching the next page if needed,
+// it returns false at the end of the history or on error.
+func (it *TradeHistoryIterator) NextContext(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	if len(it.trades) == 0 {
+		if !it.more {
+			return false
+		}
+
+		page, err := it.session.GetTradeHistoryContext(ctx, &it.options)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		if len(page.Trades) == 0 {
+			it.more = false
+			return false
+		}
+
+		it.trades = page.Trades
+		it.more = page.More
+
+		last := page.Trades[len(page.Trades)-1]
+		it.options.StartAfterTime = last.Created
+		it.options.StartAfterTradeID = last.ID
+	}
+
+	it.trade, it.trades = it.trades[0], it.trades[1:]
+	return true
+}
+
+func (it *TradeHistoryIterator) Trade() *Trade {
+	return it.trade
+}
+
+func (it *TradeHistoryIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,36 @@
+package steam
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const mixedAppsHistoryResponse = `{
+	"trades": [{
+		"tradeid": "1",
+		"assets_given": [{"appid": 730, "contextid": "2", "assetid": "10", "classid": "100", "instanceid": "0", "amount": "1"}],
+		"assets_received": [{"appid": 440, "contextid": "2", "assetid": "20", "classid": "100", "instanceid": "0", "amount": "1"}]
+	}],
+	"descriptions": [
+		{"appid": 440, "classid": "100", "instanceid": "0", "name": "TF2 item"},
+		{"appid": 730, "classid": "100", "instanceid": "0", "name": "CS item"}
+	]
+}`
+
+func TestTradeHistoryDescriptionsMixedApps(t *testing.T) {
+	var response tradeHistoryResponse
+	if err := json.Unmarshal([]byte(mixedAppsHistoryResponse), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	response.attachDescriptions()
+
+	trade := response.Trades[0]
+	if desc := trade.AssetsGiven[0].Desc; desc == nil || desc.Name != "CS item" {
+		t.Fatalf("given asset: got %+v, want CS item", desc)
+	}
+
+	if desc := trade.AssetsReceived[0].Desc; desc == nil || desc.Name != "TF2 item" {
+		t.Fatalf("received asset: got %+v, want TF2 item", desc)
+	}
+}
@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -71,6 +72,8 @@ type EconItem struct {
 	ContextID  uint64 `json:"contextid,string"`
 	Amount     uint16 `json:"amount,string"`
 	Missing    bool   `json:"missing,omitempty"`
+	/* Set when descriptions were requested, may be nil  */
+	Desc *EconItemDesc `json:"-"`
 }
 
 type EconDesc struct {
@@ -92,6 +95,7 @@ type EconAction struct {
 }
 
 type EconItemDesc struct {
+	AppID           uint32        `json:"appid"`             // for matching with EconItem
 	ClassID         uint64        `json:"classid,string"`    // for matching with EconItem
 	InstanceID      uint64        `json:"instanceid,string"` // for matching with EconItem
 	Tradable        int           `json:"tradable"`
@@ -109,6 +113,27 @@ type EconItemDesc struct {
 	Descriptions    []*EconDesc   `json:"descriptions"`
 }
 
+// econDescriptions indexes descriptions by "<APP_ID>_<CLASS_ID>_<INSTANCE_ID>",
+// class IDs are only unique within an app and offers can mix apps.
+type econDescriptions map[string]*EconItemDesc
+
+func newEconDescriptions(descs []*EconItemDesc) econDescriptions {
+	descriptions := make(econDescriptions, len(descs))
+	for _, desc := range descs {
+		descriptions.add(desc)
+	}
+
+	return descriptions
+}
+
+func (descriptions econDescriptions) add(desc *EconItemDesc) {
+	descriptions[fmt.Sprintf("%d_%d_%d", desc.AppID, desc.ClassID, desc.InstanceID)] = desc
+}
+
+func (descriptions econDescriptions) find(appID uint32, classID, instanceID uint64) *EconItemDesc {
+	return descriptions[fmt.Sprintf("%d_%d_%d", appID, classID, instanceID)]
+}
+
 type TradeOffer struct {
 	ID                 uint64      `json:"tradeofferid,string"`
 	Partner            uint32      `json:"accountid_other"`
@@ -130,7 +155,28 @@ type TradeOfferResponse struct {
 	Offer          *TradeOffer     `json:"offer"`                 // GetTradeOffer
 	SentOffers     []*TradeOffer   `json:"trade_offers_sent"`     // GetTradeOffers
 	ReceivedOffers []*TradeOffer   `json:"trade_offers_received"` // GetTradeOffers
-	Descriptions   []*EconItemDesc `json:"descriptions"`          // GetTradeOffers
+	Descriptions   []*EconItemDesc `json:"descriptions"`          // with descriptions requested
+}
+
+// attachDescriptions sets Desc of every item in the offers of @response.
+func (response *TradeOfferResponse) attachDescriptions() {
+	if len(response.Descriptions) == 0 {
+		return
+	}
+
+	descriptions := newEconDescriptions(response.Descriptions)
+	offers := append(append([]*TradeOffer{}, response.SentOffers...), response.ReceivedOffers...)
+	if response.Offer != nil {
+		offers = append(offers, response.Offer)
+	}
+
+	for _, offer := range offers {
+		for _, items := range [][]*EconItem{offer.RecvItems, offer.SendItems} {
+			for _, item := range items {
+				item.Desc = descriptions.find(item.AppID, item.ClassID, item.InstanceID)
+			}
+		}
+	}
 }
 
 type APIResponse struct {
@@ -138,10 +184,32 @@ type APIResponse struct {
 }
 
 func (session *Session) GetTradeOffer(id uint64) (*TradeOffer, error) {
-	resp, err := session.client.Get(apiGetTradeOffer + url.Values{
+	return session.GetTradeOfferContext(context.Background(), id)
+}
+
+func (session *Session) GetTradeOfferContext(ctx context.Context, id uint64) (*TradeOffer, error) {
+	return session.getTradeOffer(ctx, id, false)
+}
+
+// GetTradeOfferWithDescriptions is GetTradeOffer with Desc set on the items.
+func (session *Session) GetTradeOfferWithDescriptions(id uint64) (*TradeOffer, error) {
+	return session.GetTradeOfferWithDescriptionsContext(context.Background(), id)
+}
+
+func (session *Session) GetTradeOfferWithDescriptionsContext(ctx context.Context, id uint64) (*TradeOffer, error) {
+	return session.getTradeOffer(ctx, id, true)
+}
+
+func (session *Session) getTradeOffer(ctx context.Context, id uint64, descriptions bool) (*TradeOffer, error) {
+	params := url.Values{
 		"key":          {session.apiKey},
 		"tradeofferid": {strconv.FormatUint(id, 10)},
-	}.Encode())
+	}
+	if descriptions {
+		params.Set("get_descriptions", "1")
+	}
+
+	resp, err := session.get(ctx, apiGetTradeOffer+params.Encode())
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -155,6 +223,11 @@ func (session *Session) GetTradeOffer(id uint64) (*TradeOffer, error) {
 		return nil, err
 	}
 
+	if response.Inner == nil {
+		return nil, ErrEmptyTradeOffersResponse
+	}
+
+	response.Inner.attachDescriptions()
 	return response.Inner.Offer, nil
 }
 
@@ -163,6 +236,10 @@ func testBit(bits uint32, bit uint32) bool {
 }
 
 func (session *Session) GetTradeOffers(filter uint32, timeCutOff time.Time) (*TradeOfferResponse, error) {
+	return session.GetTradeOffersContext(context.Background(), filter, timeCutOff)
+}
+
+func (session *Session) GetTradeOffersContext(ctx context.Context, filter uint32, timeCutOff time.Time) (*TradeOfferResponse, error) {
 	params := url.Values{
 		"key": {session.apiKey},
 	}
@@ -187,7 +264,11 @@ func (session *Session) GetTradeOffers(filter uint32, timeCutOff time.Time) (*Tr
 		params.Set("time_historical_cutoff", strconv.FormatInt(timeCutOff.Unix(), 10))
 	}
 
-	resp, err := session.client.Get(apiGetTradeOffers + params.Encode())
+	return session.getTradeOffers(ctx, params)
+}
+
+func (session *Session) getTradeOffers(ctx context.Context, params url.Values) (*TradeOfferResponse, error) {
+	resp, err := session.get(ctx, apiGetTradeOffers+params.Encode())
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -201,11 +282,19 @@ func (session *Session) GetTradeOffers(filter uint32, timeCutOff time.Time) (*Tr
 		return nil, err
 	}
 
+	if response.Inner != nil {
+		response.Inner.attachDescriptions()
+	}
+
 	return response.Inner, nil
 }
 
 func (session *Session) GetMyTradeToken() (string, error) {
-	resp, err := session.client.Get("https://steamcommunity.com/my/tradeoffers/privacy")
+	return session.GetMyTradeTokenContext(context.Background())
+}
+
+func (session *Session) GetMyTradeTokenContext(ctx context.Context) (string, error) {
+	resp, err := session.get(ctx, "https://steamcommunity.com/my/tradeoffers/privacy")
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -214,6 +303,10 @@ func (session *Session) GetMyTradeToken() (string, error) {
 		return "", err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return "", err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("http error: %d", resp.StatusCode)
 	}
@@ -238,7 +331,11 @@ type EscrowSteamGuardInfo struct {
 }
 
 func (session *Session) GetEscrowGuardInfo(sid SteamID, token string) (*EscrowSteamGuardInfo, error) {
-	resp, err := session.client.Get("https://steamcommunity.com/tradeoffer/new/?" + url.Values{
+	return session.GetEscrowGuardInfoContext(context.Background(), sid, token)
+}
+
+func (session *Session) GetEscrowGuardInfoContext(ctx context.Context, sid SteamID, token string) (*EscrowSteamGuardInfo, error) {
+	resp, err := session.get(ctx, "https://steamcommunity.com/tradeoffer/new/?"+url.Values{
 		"partner": {strconv.FormatUint(uint64(sid.GetAccountID()), 10)},
 		"token":   {token},
 	}.Encode())
@@ -250,6 +347,10 @@ func (session *Session) GetEscrowGuardInfo(sid SteamID, token string) (*EscrowSt
 		return nil, err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return nil, err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
 	}
@@ -286,6 +387,10 @@ func (session *Session) GetEscrowGuardInfo(sid SteamID, token string) (*EscrowSt
 }
 
 func (session *Session) SendTradeOffer(offer *TradeOffer, sid SteamID, token string) error {
+	return session.SendTradeOfferContext(context.Background(), offer, sid, token)
+}
+
+func (session *Session) SendTradeOfferContext(ctx context.Context, offer *TradeOffer, sid SteamID, token string) error {
 	content := map[string]interface{}{
 		"newversion": true,
 		"version":    3,
@@ -306,7 +411,8 @@ func (session *Session) SendTradeOffer(offer *TradeOffer, sid SteamID, token str
 		return err
 	}
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		"https://steamcommunity.com/tradeoffer/new/send",
 		strings.NewReader(url.Values{
@@ -336,6 +442,10 @@ func (session *Session) SendTradeOffer(offer *TradeOffer, sid SteamID, token str
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	type Response struct {
 		ErrorMessage               string `json:"strError"`
 		ID                         uint64 `json:"tradeofferid,string"`
@@ -377,7 +487,11 @@ func (session *Session) SendTradeOffer(offer *TradeOffer, sid SteamID, token str
 }
 
 func (session *Session) GetTradeReceivedItems(receiptID uint64) ([]*InventoryItem, error) {
-	resp, err := session.client.Get(fmt.Sprintf("https://steamcommunity.com/trade/%d/receipt", receiptID))
+	return session.GetTradeReceivedItemsContext(context.Background(), receiptID)
+}
+
+func (session *Session) GetTradeReceivedItemsContext(ctx context.Context, receiptID uint64) ([]*InventoryItem, error) {
+	resp, err := session.get(ctx, fmt.Sprintf("https://steamcommunity.com/trade/%d/receipt", receiptID))
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -386,6 +500,10 @@ func (session *Session) GetTradeReceivedItems(receiptID uint64) ([]*InventoryIte
 		return nil, err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return nil, err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("http error: %d", resp.StatusCode)
 	}
@@ -414,7 +532,11 @@ func (session *Session) GetTradeReceivedItems(receiptID uint64) ([]*InventoryIte
 }
 
 func (session *Session) DeclineTradeOffer(id uint64) error {
-	resp, err := session.client.PostForm(apiDeclineTradeOffer, url.Values{
+	return session.DeclineTradeOfferContext(context.Background(), id)
+}
+
+func (session *Session) DeclineTradeOfferContext(ctx context.Context, id uint64) error {
+	resp, err := session.postForm(ctx, apiDeclineTradeOffer, url.Values{
 		"key":          {session.apiKey},
 		"tradeofferid": {strconv.FormatUint(id, 10)},
 	})
@@ -435,7 +557,11 @@ func (session *Session) DeclineTradeOffer(id uint64) error {
 }
 
 func (session *Session) CancelTradeOffer(id uint64) error {
-	resp, err := session.client.PostForm(apiCancelTradeOffer, url.Values{
+	return session.CancelTradeOfferContext(context.Background(), id)
+}
+
+func (session *Session) CancelTradeOfferContext(ctx context.Context, id uint64) error {
+	resp, err := session.postForm(ctx, apiCancelTradeOffer, url.Values{
 		"key":          {session.apiKey},
 		"tradeofferid": {strconv.FormatUint(id, 10)},
 	})
@@ -456,10 +582,15 @@ func (session *Session) CancelTradeOffer(id uint64) error {
 }
 
 func (session *Session) AcceptTradeOffer(id uint64) error {
+	return session.AcceptTradeOfferContext(context.Background(), id)
+}
+
+func (session *Session) AcceptTradeOfferContext(ctx context.Context, id uint64) error {
 	tid := strconv.FormatUint(id, 10)
 	postURL := "https://steamcommunity.com/tradeoffer/" + tid
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		postURL+"/accept",
 		strings.NewReader(url.Values{
@@ -484,6 +615,10 @@ func (session *Session) AcceptTradeOffer(id uint64) error {
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("http error: %d", resp.StatusCode)
 	}
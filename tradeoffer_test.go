@@ -0,0 +1,37 @@
+package steam
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Two apps can use the same class ID, an offer mixing them must not swap descriptions.
+const mixedAppsOfferResponse = `{
+	"trade_offers_received": [{
+		"tradeofferid": "1",
+		"items_to_give": [{"appid": 730, "contextid": "2", "assetid": "10", "classid": "100", "instanceid": "0", "amount": "1"}],
+		"items_to_receive": [{"appid": 440, "contextid": "2", "assetid": "20", "classid": "100", "instanceid": "0", "amount": "1"}]
+	}],
+	"descriptions": [
+		{"appid": 440, "classid": "100", "instanceid": "0", "name": "TF2 item"},
+		{"appid": 730, "classid": "100", "instanceid": "0", "name": "CS item"}
+	]
+}`
+
+func TestAttachDescriptionsMixedApps(t *testing.T) {
+	var response TradeOfferResponse
+	if err := json.Unmarshal([]byte(mixedAppsOfferResponse), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	response.attachDescriptions()
+
+	offer := response.ReceivedOffers[0]
+	if desc := offer.SendItems[0].Desc; desc == nil || desc.Name != "CS item" {
+		t.Fatalf("given item: got %+v, want CS item", desc)
+	}
+
+	if desc := offer.RecvItems[0].Desc; desc == nil || desc.Name != "TF2 item" {
+		t.Fatalf("received item: got %+v, want TF2 item", desc)
+	}
+}
@@ -0,0 +1,331 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TradeOfferEvent.Type values
+const (
+	// A received offer we did not know of yet is active.
+	TradeOfferEventNew = iota
+	// A known offer changed to a state without a dedicated event.
+	TradeOfferEventStateChanged
+	TradeOfferEventAccepted
+	TradeOfferEventDeclined
+	TradeOfferEventExpired
+	TradeOfferEventCountered
+	TradeOfferEventEscrow
+)
+
+var ErrEmptyTradeOffersResponse = errors.New("empty trade offers response")
+
+// Offers updated that long before the previous poll are fetched again,
+// to make up for clock differences with Steam.
+const tradeOfferPollMargin = 5 * time.Minute
+
+// TradeOfferEvent is sent by TradeOfferManager, OldState is TradeStateNone
+// for TradeOfferEventNew.
+type TradeOfferEvent struct {
+	Type     int
+	Offer    *TradeOffer
+	OldState uint8
+}
+
+// PollData is what TradeOfferManager has to remember across restarts.
+type PollData struct {
+	LastPoll    int64            `json:"last_poll"`
+	OfferStates map[uint64]uint8 `json:"offer_states"`
+}
+
+// PollDataStorage persists the poll data of a TradeOfferManager,
+// LoadPollData returns nil and no error when nothing was saved yet.
+type PollDataStorage interface {
+	LoadPollData() (*PollData, error)
+	SavePollData(data *PollData) error
+}
+
+// FilePollDataStorage keeps poll data as JSON in a file.
+type FilePollDataStorage string
+
+func (path FilePollDataStorage) LoadPollData() (*PollData, error) {
+	data, err := ioutil.ReadFile(string(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	pollData := &PollData{}
+	if err = json.Unmarshal(data, pollData); err != nil {
+		return nil, err
+	}
+
+	return pollData, nil
+}
+
+func (path FilePollDataStorage) SavePollData(pollData *PollData) error {
+	data, err := json.Marshal(pollData)
+	if err != nil {
+		return err
+	}
+
+	// Written aside and renamed over, so a crash never leaves half a file.
+	name, err := writeTempFile(filepath.Dir(string(path)), data)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Rename(name, string(path)); err != nil {
+		os.Remove(name)
+		return err
+	}
+
+	return nil
+}
+
+// TradeOfferManager polls sent and received trade offers and reports what
+// changed since the previous poll on Events.
+type TradeOfferManager struct {
+	session  *Session
+	storage  PollDataStorage
+	interval time.Duration
+
+	data   *PollData
+	events chan *TradeOfferEvent
+	errors chan error
+
+	startOnce sync.Once
+	startErr  error
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewTradeOfferManager returns a manager polling every @interval, a nil
+// @storage keeps poll data in memory only, events then fire again on restart.
+func NewTradeOfferManager(session *Session, storage PollDataStorage, interval time.Duration) *TradeOfferManager {
+	return &TradeOfferManager{
+		session:  session,
+		storage:  storage,
+		interval: interval,
+		events:   make(chan *TradeOfferEvent),
+		errors:   make(chan error, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+// Events is closed once the manager stopped.
+func (manager *TradeOfferManager) Events() <-chan *TradeOfferEvent {
+	return manager.events
+}
+
+// Errors reports failed polls, those not read before the next one are dropped.
+func (manager *TradeOfferManager) Errors() <-chan error {
+	return manager.errors
+}
+
+// Start loads poll data and polls until @ctx is done or Stop is called,
+// if loading failed the manager is stopped and Start keeps returning that error.
+func (manager *TradeOfferManager) Start(ctx context.Context) error {
+	manager.startOnce.Do(func() {
+		if manager.storage != nil {
+			var err error
+			if manager.data, err = manager.storage.LoadPollData(); err != nil {
+				manager.startErr = err
+				close(manager.events)
+				close(manager.done)
+				return
+			}
+		}
+
+		if manager.data == nil {
+			manager.data = &PollData{}
+		}
+
+		if manager.data.OfferStates == nil {
+			manager.data.OfferStates = make(map[uint64]uint8)
+		}
+
+		ctx, manager.cancel = context.WithCancel(ctx)
+		go manager.run(ctx)
+	})
+
+	return manager.startErr
+}
+
+// Stop ends polling and waits for the poll in progress to complete,
+// a manager never started cannot be started afterwards.
+func (manager *TradeOfferManager) Stop() {
+	started := true
+	manager.startOnce.Do(func() {
+		started = false
+		close(manager.events)
+		close(manager.done)
+	})
+
+	if started && manager.cancel != nil {
+		manager.cancel()
+	}
+
+	<-manager.done
+}
+
+func (manager *TradeOfferManager) run(ctx context.Context) {
+	defer close(manager.done)
+	defer close(manager.events)
+
+	ticker := time.NewTicker(manager.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := manager.poll(ctx); err != nil && ctx.Err() == nil {
+			select {
+			case manager.errors <- err:
+			default:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func tradeOfferEventType(state uint8) int {
+	switch state {
+	case TradeStateAccepted:
+		return TradeOfferEventAccepted
+	case TradeStateDeclined:
+		return TradeOfferEventDeclined
+	case TradeStateExpired:
+		return TradeOfferEventExpired
+	case TradeStateCountered:
+		return TradeOfferEventCountered
+	case TradeStateInEscrow:
+		return TradeOfferEventEscrow
+	}
+
+	return TradeOfferEventStateChanged
+}
+
+// tradeOfferFinal tells if an offer in @state can still change.
+func tradeOfferFinal(state uint8) bool {
+	switch state {
+	case TradeStateActive, TradeStateCreatedNeedsConfirmation, TradeStateInEscrow:
+		return false
+	}
+
+	return true
+}
+
+// getOffers returns sent and received offers that are active or were updated
+// after @cutoff, GetTradeOffers only takes a cutoff for historical offers.
+func (manager *TradeOfferManager) getOffers(ctx context.Context, cutoff time.Time) (*TradeOfferResponse, error) {
+	params := url.Values{
+		"key":                 {manager.session.apiKey},
+		"get_sent_offers":     {"1"},
+		"get_received_offers": {"1"},
+		"active_only":         {"1"},
+		"get_descriptions":    {"1"},
+	}
+	if !cutoff.IsZero() {
+		params.Set("time_historical_cutoff", strconv.FormatInt(cutoff.Unix(), 10))
+	}
+
+	response, err := manager.session.getTradeOffers(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if response == nil {
+		return nil, ErrEmptyTradeOffersResponse
+	}
+
+	return response, nil
+}
+
+func (manager *TradeOfferManager) save() error {
+	if manager.storage == nil {
+		return nil
+	}
+
+	return manager.storage.SavePollData(manager.data)
+}
+
+func (manager *TradeOfferManager) poll(ctx context.Context) error {
+	var cutoff time.Time
+	if manager.data.LastPoll != 0 {
+		cutoff = time.Unix(manager.data.LastPoll, 0).Add(-tradeOfferPollMargin)
+	}
+
+	now := time.Now()
+	response, err := manager.getOffers(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+
+	// States of offers with an event are only recorded once it was delivered.
+	var events []*TradeOfferEvent
+	seen := make(map[uint64]bool)
+	track := func(offer *TradeOffer, received bool) {
+		seen[offer.ID] = true
+		oldState, known := manager.data.OfferStates[offer.ID]
+
+		switch {
+		case !known && received && offer.State == TradeStateActive:
+			events = append(events, &TradeOfferEvent{Type: TradeOfferEventNew, Offer: offer})
+		case known && oldState != offer.State:
+			events = append(events, &TradeOfferEvent{
+				Type:     tradeOfferEventType(offer.State),
+				Offer:    offer,
+				OldState: oldState,
+			})
+		case !known:
+			manager.data.OfferStates[offer.ID] = offer.State
+		}
+	}
+
+	for _, offer := range response.SentOffers {
+		track(offer, false)
+	}
+
+	for _, offer := range response.ReceivedOffers {
+		track(offer, true)
+	}
+
+	// Offers that cannot change anymore and were not returned are done with.
+	for id, state := range manager.data.OfferStates {
+		if !seen[id] && tradeOfferFinal(state) {
+			delete(manager.data.OfferStates, id)
+		}
+	}
+
+	// Saved after every delivered event, so a restart does not fire it again.
+	for _, event := range events {
+		select {
+		case manager.events <- event:
+		case <-ctx.Done():
+			return nil
+		}
+
+		manager.data.OfferStates[event.Offer.ID] = event.Offer.State
+		if err = manager.save(); err != nil {
+			return err
+		}
+	}
+
+	manager.data.LastPoll = now.Unix()
+	return manager.save()
+}
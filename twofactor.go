@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/url"
@@ -18,26 +19,76 @@ type TwoFactorInfo struct {
 	URI            string `json:"uri"`
 	ServerTime     uint64 `json:"server_time,string"`
 	TokenGID       string `json:"token_gid"`
+	AccountName    string `json:"account_name"`
 }
 
 type FinalizeTwoFactorInfo struct {
 	Status     uint32 `json:"status"`
 	ServerTime uint64 `json:"server_time,string"`
+	WantMore   bool   `json:"want_more"`
+	Success    bool   `json:"success"`
+}
+
+// TwoFactorStatus.SteamGuardScheme values
+const (
+	SteamGuardSchemeNone = iota
+	SteamGuardSchemeEmail
+	SteamGuardSchemeMobile
+)
+
+// TwoFactorStatus is the authenticator state Steam reports for the account.
+type TwoFactorStatus struct {
+	State                       uint32 `json:"state"`
+	InactivationReason          uint32 `json:"inactivation_reason"`
+	AuthenticatorType           uint32 `json:"authenticator_type"`
+	AuthenticatorAllowed        bool   `json:"authenticator_allowed"`
+	SteamGuardScheme            uint32 `json:"steamguard_scheme"`
+	TokenGID                    string `json:"token_gid"`
+	EmailValidated              bool   `json:"email_validated"`
+	DeviceIdentifier            string `json:"device_identifier"`
+	RevocationAttemptsRemaining uint32 `json:"revocation_attempts_remaining"`
+	ClassifiedAgent             string `json:"classified_agent"`
+	AllowExternalAuthenticator  bool   `json:"allow_external_authenticator"`
+	Version                     uint32 `json:"version"`
+	// TimeCreated and TimeTransferred are zero when Steam does not tell.
+	TimeCreated     time.Time `json:"-"`
+	TimeTransferred time.Time `json:"-"`
+}
+
+// HasMobileAuthenticator tells if the account is protected by a mobile authenticator.
+func (status *TwoFactorStatus) HasMobileAuthenticator() bool {
+	return status.SteamGuardScheme == SteamGuardSchemeMobile
 }
 
 const (
 	enableTwoFactorURL   = "https://api.steampowered.com/ITwoFactorService/AddAuthenticator/v1/"
 	finalizeTwoFactorURL = "https://api.steampowered.com/ITwoFactorService/FinalizeAddAuthenticator/v1/"
 	disableTwoFactorURL  = "https://api.steampowered.com/ITwoFactorService/RemoveAuthenticator/v1/"
+	queryTwoFactorURL    = "https://api.steampowered.com/ITwoFactorService/QueryStatus/v1/"
+
+	moveTwoFactorStartURL    = "https://api.steampowered.com/ITwoFactorService/RemoveAuthenticatorViaChallengeStart/v1/"
+	moveTwoFactorContinueURL = "https://api.steampowered.com/ITwoFactorService/RemoveAuthenticatorViaChallengeContinue/v1/"
 )
 
-var ErrCannotDisable = errors.New("unable to process disable two factor request")
+var (
+	ErrCannotDisable = errors.New("unable to process disable two factor request")
+	ErrCannotMove    = errors.New("unable to move two factor to this device")
+)
 
 func (session *Session) EnableTwoFactor() (*TwoFactorInfo, error) {
-	resp, err := session.client.PostForm(enableTwoFactorURL, url.Values{
+	return session.EnableTwoFactorContext(context.Background())
+}
+
+func (session *Session) EnableTwoFactorContext(ctx context.Context) (*TwoFactorInfo, error) {
+	now, err := session.now(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := session.postForm(ctx, enableTwoFactorURL, url.Values{
 		"steamid":            {session.oauth.SteamID.ToString()},
 		"access_token":       {session.oauth.Token},
-		"authenticator_time": {strconv.FormatInt(time.Now().Unix(), 10)},
+		"authenticator_time": {strconv.FormatInt(now.Unix(), 10)},
 		"authenticator_type": {"1"}, /* 1 = Valve's, 2 = thirdparty  */
 		"device_identifier":  {session.deviceID},
 		"sms_phone_id":       {"1"},
@@ -63,10 +114,19 @@ func (session *Session) EnableTwoFactor() (*TwoFactorInfo, error) {
 }
 
 func (session *Session) FinalizeTwoFactor(authCode, mobileCode string) (*FinalizeTwoFactorInfo, error) {
-	resp, err := session.client.PostForm(finalizeTwoFactorURL, url.Values{
+	return session.FinalizeTwoFactorContext(context.Background(), authCode, mobileCode)
+}
+
+func (session *Session) FinalizeTwoFactorContext(ctx context.Context, authCode, mobileCode string) (*FinalizeTwoFactorInfo, error) {
+	now, err := session.now(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := session.postForm(ctx, finalizeTwoFactorURL, url.Values{
 		"steamid":            {session.oauth.SteamID.ToString()},
 		"access_token":       {session.oauth.Token},
-		"authenticator_time": {strconv.FormatInt(time.Now().Unix(), 10)},
+		"authenticator_time": {strconv.FormatInt(now.Unix(), 10)},
 		"authenticator_code": {authCode},
 		"activation_code":    {mobileCode},
 	})
@@ -91,7 +151,11 @@ func (session *Session) FinalizeTwoFactor(authCode, mobileCode string) (*Finaliz
 }
 
 func (session *Session) DisableTwoFactor(revocationCode string) error {
-	resp, err := session.client.PostForm(disableTwoFactorURL, url.Values{
+	return session.DisableTwoFactorContext(context.Background(), revocationCode)
+}
+
+func (session *Session) DisableTwoFactorContext(ctx context.Context, revocationCode string) error {
+	resp, err := session.postForm(ctx, disableTwoFactorURL, url.Values{
 		"steamid":           {session.oauth.SteamID.ToString()},
 		"access_token":      {session.oauth.Token},
 		"revocation_code":   {revocationCode},
@@ -123,3 +187,145 @@ func (session *Session) DisableTwoFactor(revocationCode string) error {
 
 	return nil
 }
+
+// QueryTwoFactor returns the authenticator status of the account, compare
+// TokenGID with the one stored to tell if it was replaced. QueryStatus does
+// not return the serial number, only AddAuthenticator and the maFile have it.
+func (session *Session) QueryTwoFactor() (*TwoFactorStatus, error) {
+	return session.QueryTwoFactorContext(context.Background())
+}
+
+func (session *Session) QueryTwoFactorContext(ctx context.Context) (*TwoFactorStatus, error) {
+	resp, err := session.postForm(ctx, queryTwoFactorURL, url.Values{
+		"steamid":      {session.oauth.SteamID.ToString()},
+		"access_token": {session.oauth.Token},
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkEResult(resp); err != nil {
+		return nil, err
+	}
+
+	type Status struct {
+		TwoFactorStatus
+		TimeCreated     int64 `json:"time_created"`
+		TimeTransferred int64 `json:"time_transferred"`
+	}
+	type Response struct {
+		Inner *Status `json:"response"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil {
+		return nil, ErrEmptyTwoFactorResponse
+	}
+
+	status := &response.Inner.TwoFactorStatus
+	if response.Inner.TimeCreated != 0 {
+		status.TimeCreated = time.Unix(response.Inner.TimeCreated, 0)
+	}
+
+	if response.Inner.TimeTransferred != 0 {
+		status.TimeTransferred = time.Unix(response.Inner.TimeTransferred, 0)
+	}
+
+	return status, nil
+}
+
+// MoveTwoFactor asks Steam to text an SMS code to the phone of the
+// account, FinalizeMoveTwoFactor with it takes over the authenticator.
+func (session *Session) MoveTwoFactor() error {
+	return session.MoveTwoFactorContext(context.Background())
+}
+
+func (session *Session) MoveTwoFactorContext(ctx context.Context) error {
+	resp, err := session.postForm(ctx, moveTwoFactorStartURL, url.Values{
+		"access_token": {session.oauth.Token},
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = checkEResult(resp); err != nil {
+		return err
+	}
+
+	type Started struct {
+		Success bool `json:"success"`
+	}
+	type Response struct {
+		Inner *Started `json:"response"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return err
+	}
+
+	if response.Inner == nil || !response.Inner.Success {
+		return ErrCannotMove
+	}
+
+	return nil
+}
+
+// FinalizeMoveTwoFactor moves the authenticator to the device ID of the
+// session without revoking it, so no trade hold applies.
+// The returned secrets replace the old ones, which stop working.
+// @smsCode is the code texted after MoveTwoFactor.
+func (session *Session) FinalizeMoveTwoFactor(smsCode string) (*TwoFactorInfo, error) {
+	return session.FinalizeMoveTwoFactorContext(context.Background(), smsCode)
+}
+
+func (session *Session) FinalizeMoveTwoFactorContext(ctx context.Context, smsCode string) (*TwoFactorInfo, error) {
+	resp, err := session.postForm(ctx, moveTwoFactorContinueURL, url.Values{
+		"access_token":       {session.oauth.Token},
+		"sms_code":           {smsCode},
+		"generate_new_token": {"1"},
+		"version":            {"2"},
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkEResult(resp); err != nil {
+		return nil, err
+	}
+
+	type Moved struct {
+		Success          bool           `json:"success"`
+		ReplacementToken *TwoFactorInfo `json:"replacement_token"`
+	}
+	type Response struct {
+		Inner *Moved `json:"response"`
+	}
+
+	var response Response
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if response.Inner == nil || !response.Inner.Success || response.Inner.ReplacementToken == nil {
+		return nil, ErrCannotMove
+	}
+
+	return response.Inner.ReplacementToken, nil
+}
@@ -0,0 +1,255 @@
+package steam
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Confirmation answers, as taken by AnswerConfirmation.
+const (
+	ConfirmationAllow  = "allow"
+	ConfirmationCancel = "cancel"
+	// ConfirmationIgnore leaves the confirmation pending.
+	ConfirmationIgnore = ""
+	// ConfirmationLater asks the policy again on the next poll,
+	// the confirmation is only reported once decided.
+	ConfirmationLater = "later"
+)
+
+const (
+	defaultConfirmationGracePeriod = time.Minute
+	expectedTradeOfferTTL          = 24 * time.Hour
+)
+
+// ConfirmationPolicy decides how a new confirmation is answered, it returns
+// one of the Confirmation answers above.
+type ConfirmationPolicy func(confirmation *Confirmation) string
+
+// ConfirmationEvent is sent by ConfirmationWatcher for every new confirmation,
+// Answer is what the policy decided and Err why answering failed, if it did.
+type ConfirmationEvent struct {
+	Confirmation *Confirmation
+	Answer       string
+	Err          error
+}
+
+// ConfirmationRules is a ConfirmationPolicy allowing trade confirmations of
+// offers registered with ExpectTradeOffer and, if AllowMarketListings is set,
+// market listings. Everything else gets the Otherwise answer, except trade
+// confirmations younger than GracePeriod (a minute if zero): their offer may
+// not be registered yet as SendTradeOffer did not return, they wait.
+type ConfirmationRules struct {
+	AllowMarketListings bool
+	Otherwise           string
+	GracePeriod         time.Duration
+
+	mu     sync.Mutex
+	offers map[uint64]time.Time
+}
+
+// ExpectTradeOffer registers the ID of an offer sent with SendTradeOffer,
+// it is forgotten after a day.
+func (rules *ConfirmationRules) ExpectTradeOffer(offerID uint64) {
+	rules.mu.Lock()
+	defer rules.mu.Unlock()
+
+	if rules.offers == nil {
+		rules.offers = make(map[uint64]time.Time)
+	}
+
+	now := time.Now()
+	for id, expected := range rules.offers {
+		if now.Sub(expected) > expectedTradeOfferTTL {
+			delete(rules.offers, id)
+		}
+	}
+
+	rules.offers[offerID] = now
+}
+
+// Answer does not forget the offer, so a failed answer is decided the same way again.
+func (rules *ConfirmationRules) Answer(confirmation *Confirmation) string {
+	switch confirmation.Type {
+	case ConfirmationTypeTrade:
+		rules.mu.Lock()
+		_, expected := rules.offers[confirmation.OfferID]
+		rules.mu.Unlock()
+
+		if expected {
+			return ConfirmationAllow
+		}
+
+		gracePeriod := rules.GracePeriod
+		if gracePeriod == 0 {
+			gracePeriod = defaultConfirmationGracePeriod
+		}
+
+		if time.Since(confirmation.CreationTime) < gracePeriod {
+			return ConfirmationLater
+		}
+	case ConfirmationTypeMarketListing:
+		if rules.AllowMarketListings {
+			return ConfirmationAllow
+		}
+	}
+
+	return rules.Otherwise
+}
+
+// ConfirmationWatcher polls the confirmations of a session, applies the
+// policy to every one it did not see yet and reports them on Events.
+// Confirmations whose answer failed are retried on the next poll.
+type ConfirmationWatcher struct {
+	session        *Session
+	identitySecret string
+	interval       time.Duration
+	policy         ConfirmationPolicy
+
+	events chan *ConfirmationEvent
+	errors chan error
+	seen   map[uint64]bool
+
+	startOnce sync.Once
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewConfirmationWatcher returns a watcher polling every @interval,
+// a nil @policy only reports confirmations without answering them.
+// Events must be drained even when only @policy matters, the watcher
+// waits for every event to be read before it polls again.
+func NewConfirmationWatcher(session *Session, identitySecret string, interval time.Duration, policy ConfirmationPolicy) *ConfirmationWatcher {
+	return &ConfirmationWatcher{
+		session:        session,
+		identitySecret: identitySecret,
+		interval:       interval,
+		policy:         policy,
+		events:         make(chan *ConfirmationEvent),
+		errors:         make(chan error, 1),
+		seen:           make(map[uint64]bool),
+		done:           make(chan struct{}),
+	}
+}
+
+// Events is closed once the watcher stopped, polling is blocked until
+// the events of the previous poll were read.
+func (watcher *ConfirmationWatcher) Events() <-chan *ConfirmationEvent {
+	return watcher.events
+}
+
+// Errors reports failed polls, those not read before the next one are dropped.
+func (watcher *ConfirmationWatcher) Errors() <-chan error {
+	return watcher.errors
+}
+
+// Start polls until @ctx is done or Stop is called.
+func (watcher *ConfirmationWatcher) Start(ctx context.Context) {
+	watcher.startOnce.Do(func() {
+		ctx, watcher.cancel = context.WithCancel(ctx)
+		go watcher.run(ctx)
+	})
+}
+
+// Stop ends polling and waits for an answer in progress to complete,
+// a watcher never started cannot be started afterwards.
+func (watcher *ConfirmationWatcher) Stop() {
+	started := true
+	watcher.startOnce.Do(func() {
+		started = false
+		close(watcher.events)
+		close(watcher.done)
+	})
+
+	if started && watcher.cancel != nil {
+		watcher.cancel()
+	}
+
+	<-watcher.done
+}
+
+func (watcher *ConfirmationWatcher) run(ctx context.Context) {
+	defer close(watcher.done)
+	defer close(watcher.events)
+
+	ticker := time.NewTicker(watcher.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := watcher.poll(ctx); err != nil && ctx.Err() == nil {
+			select {
+			case watcher.errors <- err:
+			default:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (watcher *ConfirmationWatcher) poll(ctx context.Context) error {
+	confirmations, err := watcher.session.GetConfirmationsContext(ctx, watcher.identitySecret, 0)
+	if err != nil {
+		return err
+	}
+
+	// Only remember what is still pending, so seen does not grow forever.
+	seen := make(map[uint64]bool, len(confirmations))
+	answers := make(map[string][]*Confirmation)
+	var fresh []*ConfirmationEvent
+	for _, confirmation := range confirmations {
+		if watcher.seen[confirmation.ID] {
+			seen[confirmation.ID] = true
+			continue
+		}
+
+		event := &ConfirmationEvent{Confirmation: confirmation}
+		if watcher.policy != nil {
+			event.Answer = watcher.policy(confirmation)
+		}
+
+		if event.Answer == ConfirmationLater {
+			continue
+		}
+
+		seen[confirmation.ID] = true
+
+		if event.Answer != ConfirmationIgnore {
+			answers[event.Answer] = append(answers[event.Answer], confirmation)
+		}
+
+		fresh = append(fresh, event)
+	}
+	watcher.seen = seen
+
+	failed := make(map[uint64]error)
+	for answer, batch := range answers {
+		results, err := watcher.session.AnswerConfirmationsContext(ctx, batch, watcher.identitySecret, answer, 0)
+		for k, confirmation := range batch {
+			if err != nil {
+				failed[confirmation.ID] = err
+			} else if results[k] != nil {
+				failed[confirmation.ID] = results[k]
+			}
+		}
+	}
+
+	for _, event := range fresh {
+		if err, ok := failed[event.Confirmation.ID]; ok {
+			event.Err = err
+			delete(watcher.seen, event.Confirmation.ID)
+		}
+
+		select {
+		case watcher.events <- event:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package steam
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -47,7 +48,11 @@ func (session *Session) parseKey(resp *http.Response) (string, error) {
 }
 
 func (session *Session) RegisterWebAPIKey(domain string) (string, error) {
-	resp, err := session.client.PostForm(apiKeyRegisterURL, url.Values{
+	return session.RegisterWebAPIKeyContext(context.Background(), domain)
+}
+
+func (session *Session) RegisterWebAPIKeyContext(ctx context.Context, domain string) (string, error) {
+	resp, err := session.postForm(ctx, apiKeyRegisterURL, url.Values{
 		"domain":       {domain},
 		"agreeToTerms": {"agreed"},
 		"sessionid":    {session.sessionID},
@@ -61,6 +66,10 @@ func (session *Session) RegisterWebAPIKey(domain string) (string, error) {
 		return "", err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return "", err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return "", ErrCannotRegisterKey
 	}
@@ -69,7 +78,11 @@ func (session *Session) RegisterWebAPIKey(domain string) (string, error) {
 }
 
 func (session *Session) GetWebAPIKey() (string, error) {
-	resp, err := session.client.Get(apiKeyURL)
+	return session.GetWebAPIKeyContext(context.Background())
+}
+
+func (session *Session) GetWebAPIKeyContext(ctx context.Context) (string, error) {
+	resp, err := session.get(ctx, apiKeyURL)
 	if resp != nil {
 		defer resp.Body.Close()
 	}
@@ -78,11 +91,19 @@ func (session *Session) GetWebAPIKey() (string, error) {
 		return "", err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return "", err
+	}
+
 	return session.parseKey(resp)
 }
 
 func (session *Session) RevokeWebAPIKey() error {
-	resp, err := session.client.PostForm(apiKeyRevokeURL, url.Values{
+	return session.RevokeWebAPIKeyContext(context.Background())
+}
+
+func (session *Session) RevokeWebAPIKeyContext(ctx context.Context) error {
+	resp, err := session.postForm(ctx, apiKeyRevokeURL, url.Values{
 		"Revoke":    {"Revoke My Steam Web API Key"},
 		"sessionid": {session.sessionID},
 	})
@@ -94,6 +115,10 @@ func (session *Session) RevokeWebAPIKey() error {
 		return err
 	}
 
+	if err = checkLoggedIn(resp); err != nil {
+		return err
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return ErrCannotRevokeKey
 	}